@@ -0,0 +1,287 @@
+package web
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+const (
+	defaultBreakerWindow      = 10 * time.Second
+	defaultBreakerBuckets     = 10
+	defaultBreakerMinRequests = 20
+	defaultBreakerFailureRate = 0.5
+	defaultBreakerConsecutive = 5
+	defaultBreakerOpenTimeout = 30 * time.Second
+	defaultBreakerProbes      = 1
+)
+
+//BreakerState state of circuit
+type BreakerState int
+
+func (v BreakerState) String() string {
+	switch v {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+//BreakerConfig model
+type BreakerConfig struct {
+	//Window rolling window of statistics
+	Window time.Duration `yaml:"window"`
+	//Buckets count of buckets in window
+	Buckets int `yaml:"buckets"`
+	//MinRequests min count of requests in window for failure rate check
+	MinRequests int `yaml:"min_requests"`
+	//FailureRate rate of failed requests in window to open circuit (0..1)
+	FailureRate float64 `yaml:"failure_rate"`
+	//ConsecutiveFailures count of failures in a row to open circuit
+	ConsecutiveFailures int `yaml:"consecutive_failures"`
+	//OpenTimeout time before circuit becomes half-open
+	OpenTimeout time.Duration `yaml:"open_timeout"`
+	//HalfOpenProbes count of probe requests in half-open state, all of them must succeed to close circuit
+	HalfOpenProbes int `yaml:"half_open_probes"`
+}
+
+//BreakerOpenError error of rejected request by open circuit
+type BreakerOpenError struct {
+	Host  string
+	State BreakerState
+	Until time.Time
+}
+
+func (v *BreakerOpenError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", errs.ErrCircuitOpen.Error(), v.Host, v.State)
+}
+
+//Unwrap getting errs.ErrCircuitOpen
+func (v *BreakerOpenError) Unwrap() error {
+	return errs.ErrCircuitOpen
+}
+
+//Breaker circuit breakers of outbound requests by host
+type Breaker struct {
+	conf     BreakerConfig
+	list     map[string]*circuit
+	onChange func(host string, from, to BreakerState)
+	lock     sync.Mutex
+}
+
+//NewBreaker init breaker
+func NewBreaker(conf BreakerConfig) *Breaker {
+	if conf.Window <= 0 {
+		conf.Window = defaultBreakerWindow
+	}
+	if conf.Buckets <= 0 {
+		conf.Buckets = defaultBreakerBuckets
+	}
+	if conf.MinRequests <= 0 {
+		conf.MinRequests = defaultBreakerMinRequests
+	}
+	if conf.FailureRate <= 0 || conf.FailureRate > 1 {
+		conf.FailureRate = defaultBreakerFailureRate
+	}
+	if conf.ConsecutiveFailures <= 0 {
+		conf.ConsecutiveFailures = defaultBreakerConsecutive
+	}
+	if conf.OpenTimeout <= 0 {
+		conf.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if conf.HalfOpenProbes <= 0 {
+		conf.HalfOpenProbes = defaultBreakerProbes
+	}
+	return &Breaker{
+		conf: conf,
+		list: make(map[string]*circuit),
+	}
+}
+
+//OnStateChange setting callback for changes of circuit state
+func (v *Breaker) OnStateChange(call func(host string, from, to BreakerState)) {
+	v.lock.Lock()
+	v.onChange = call
+	v.lock.Unlock()
+}
+
+//State getting state of circuit for host
+func (v *Breaker) State(host string) BreakerState {
+	c := v.circuit(host)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.state == StateOpen && !time.Now().Before(c.until) {
+		return StateHalfOpen
+	}
+	return c.state
+}
+
+//Allow checking of request to host, callback must be called with result of request
+func (v *Breaker) Allow(host string) (func(ok bool), error) {
+	release, err := v.allow(host)
+	if err != nil {
+		return nil, err
+	}
+	return func(ok bool) {
+		if ok {
+			release(outcomeSuccess)
+		} else {
+			release(outcomeFailure)
+		}
+	}, nil
+}
+
+//allow checking of request to host, skipped outcome releases half-open probe without counting
+func (v *Breaker) allow(host string) (func(outcome), error) {
+	c := v.circuit(host)
+	now := time.Now()
+
+	c.lock.Lock()
+	from := c.state
+	if c.state == StateOpen {
+		if now.Before(c.until) {
+			c.lock.Unlock()
+			return nil, &BreakerOpenError{Host: host, State: StateOpen, Until: c.until}
+		}
+		c.setState(StateHalfOpen)
+	}
+	if c.state == StateHalfOpen && c.probes >= v.conf.HalfOpenProbes {
+		c.lock.Unlock()
+		v.changed(host, from, StateHalfOpen)
+		return nil, &BreakerOpenError{Host: host, State: StateHalfOpen}
+	}
+	if c.state == StateHalfOpen {
+		c.probes++
+	}
+	gen, to := c.gen, c.state
+	c.lock.Unlock()
+	v.changed(host, from, to)
+
+	return func(res outcome) {
+		c.lock.Lock()
+		from := c.state
+		if gen == c.gen {
+			if res == outcomeSkipped {
+				c.skip()
+			} else {
+				c.record(v.conf, res == outcomeSuccess, time.Now())
+			}
+		}
+		to := c.state
+		c.lock.Unlock()
+		v.changed(host, from, to)
+	}, nil
+}
+
+func (v *Breaker) circuit(host string) *circuit {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	c, ok := v.list[host]
+	if !ok {
+		c = &circuit{buckets: make([]bucket, v.conf.Buckets)}
+		v.list[host] = c
+	}
+	return c
+}
+
+func (v *Breaker) changed(host string, from, to BreakerState) {
+	if from == to {
+		return
+	}
+	v.lock.Lock()
+	call := v.onChange
+	v.lock.Unlock()
+	if call != nil {
+		call(host, from, to)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type bucket struct {
+	start int64
+	total int
+	fails int
+}
+
+//circuit state of single host, guarded by lock
+type circuit struct {
+	state       BreakerState
+	gen         uint64
+	until       time.Time
+	buckets     []bucket
+	consecutive int
+	probes      int
+	succeeded   int
+	lock        sync.Mutex
+}
+
+func (v *circuit) setState(state BreakerState) {
+	v.state = state
+	v.gen++
+	v.probes, v.succeeded, v.consecutive = 0, 0, 0
+	for i := range v.buckets {
+		v.buckets[i] = bucket{}
+	}
+}
+
+//skip releasing of half-open probe without result
+func (v *circuit) skip() {
+	if v.state == StateHalfOpen {
+		v.probes--
+	}
+}
+
+func (v *circuit) record(conf BreakerConfig, ok bool, now time.Time) {
+	switch v.state {
+	case StateHalfOpen:
+		v.probes--
+		if !ok {
+			v.setState(StateOpen)
+			v.until = now.Add(conf.OpenTimeout)
+			return
+		}
+		if v.succeeded++; v.succeeded >= conf.HalfOpenProbes {
+			v.setState(StateClosed)
+		}
+	case StateClosed:
+		size := int64(conf.Window) / int64(len(v.buckets))
+		start := now.UnixNano() / size * size
+		b := &v.buckets[(start/size)%int64(len(v.buckets))]
+		if b.start != start {
+			*b = bucket{start: start}
+		}
+		b.total++
+		if ok {
+			v.consecutive = 0
+		} else {
+			b.fails++
+			v.consecutive++
+		}
+
+		total, fails := 0, 0
+		for _, item := range v.buckets {
+			if now.UnixNano()-item.start < int64(conf.Window) {
+				total, fails = total+item.total, fails+item.fails
+			}
+		}
+		if v.consecutive >= conf.ConsecutiveFailures ||
+			(total >= conf.MinRequests && float64(fails)/float64(total) >= conf.FailureRate) {
+			v.setState(StateOpen)
+			v.until = now.Add(conf.OpenTimeout)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_Breaker(t *testing.T) {
+	b := web.NewBreaker(web.BreakerConfig{ConsecutiveFailures: 3, OpenTimeout: 50 * time.Millisecond, HalfOpenProbes: 1})
+	changes := make([]string, 0)
+	mux := sync.Mutex{}
+	b.OnStateChange(func(host string, from, to web.BreakerState) {
+		mux.Lock()
+		changes = append(changes, host+":"+from.String()+">"+to.String())
+		mux.Unlock()
+	})
+
+	for i := 0; i < 3; i++ {
+		done, err := b.Allow("a")
+		require.NoError(t, err)
+		done(false)
+	}
+	require.Equal(t, web.StateOpen, b.State("a"))
+	require.Equal(t, web.StateClosed, b.State("b"))
+
+	_, err := b.Allow("a")
+	var openErr *web.BreakerOpenError
+	require.True(t, errors.As(err, &openErr))
+	require.True(t, errors.Is(err, errs.ErrCircuitOpen))
+	require.Equal(t, "a", openErr.Host)
+
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, web.StateHalfOpen, b.State("a"))
+	done, err := b.Allow("a")
+	require.NoError(t, err)
+	_, err = b.Allow("a")
+	require.Error(t, err)
+	done(false)
+	require.Equal(t, web.StateOpen, b.State("a"))
+
+	time.Sleep(60 * time.Millisecond)
+	done, err = b.Allow("a")
+	require.NoError(t, err)
+	done(true)
+	require.Equal(t, web.StateClosed, b.State("a"))
+
+	mux.Lock()
+	require.Equal(t, []string{
+		"a:closed>open", "a:open>half-open", "a:half-open>open", "a:open>half-open", "a:half-open>closed",
+	}, changes)
+	mux.Unlock()
+}
+
+func TestUnit_BreakerFailureRate(t *testing.T) {
+	b := web.NewBreaker(web.BreakerConfig{MinRequests: 10, FailureRate: 0.5, ConsecutiveFailures: 100})
+	for i := 0; i < 9; i++ {
+		done, err := b.Allow("a")
+		require.NoError(t, err)
+		done(i%2 == 0)
+	}
+	require.Equal(t, web.StateClosed, b.State("a"))
+	done, err := b.Allow("a")
+	require.NoError(t, err)
+	done(false)
+	require.Equal(t, web.StateOpen, b.State("a"))
+}
+
+func TestUnit_ClientBreaker(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cli := web.New()
+	cli.WithBreaker(web.NewBreaker(web.BreakerConfig{ConsecutiveFailures: 2}))
+	for i := 0; i < 2; i++ {
+		code, _, err := cli.Call(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+	}
+	_, _, err := cli.Call(http.MethodGet, srv.URL, nil)
+	require.True(t, errors.Is(err, errs.ErrCircuitOpen))
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	u, _ := url.Parse(srv.URL) //nolint: errcheck
+	require.Contains(t, err.Error(), u.Host)
+}
+
+func TestUnit_ClientBreakerPool(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{MaxFails: 2, Cooldown: time.Minute}, pool.List{srv.URL}.Targets()...)
+	require.NoError(t, err)
+
+	cli := web.New()
+	cli.WithBreaker(web.NewBreaker(web.BreakerConfig{ConsecutiveFailures: 1}))
+	cli.WithRetry(web.RetryConfig{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond, MaxDelay: 20 * time.Millisecond})
+
+	_, _, err = cli.CallPool(b, http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	//requests rejected by open circuit are not failures of endpoint
+	_, _, err = cli.CallPool(b, http.MethodGet, "/", nil)
+	require.True(t, errors.Is(err, errs.ErrCircuitOpen))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	ep := b.Endpoints()[0]
+	require.False(t, ep.Ejected())
+	require.Equal(t, int64(0), ep.Outstanding())
+}
+
+func TestUnit_ClientBreakerCanceledProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL) //nolint: errcheck
+	b := web.NewBreaker(web.BreakerConfig{ConsecutiveFailures: 1, OpenTimeout: 20 * time.Millisecond})
+	cli := web.New()
+	cli.WithBreaker(b)
+
+	_, _, err := cli.Call(http.MethodGet, srv.URL+"/fail", nil)
+	require.NoError(t, err)
+	require.Equal(t, web.StateOpen, b.State(u.Host))
+	time.Sleep(30 * time.Millisecond)
+
+	//canceled probe is not a success, circuit stays half-open and next probe is allowed
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = cli.Do(ctx, web.Request{URL: srv.URL + "/slow"})
+	require.Error(t, err)
+	require.Equal(t, web.StateHalfOpen, b.State(u.Host))
+
+	_, _, err = cli.Call(http.MethodGet, srv.URL+"/fail", nil)
+	require.NoError(t, err)
+	require.Equal(t, web.StateOpen, b.State(u.Host))
+}
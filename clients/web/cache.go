@@ -0,0 +1,329 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CacheHeader = "X-Cache"
+
+	CacheHit         = "HIT"
+	CacheMiss        = "MISS"
+	CacheRevalidated = "REVALIDATED"
+	CacheStale       = "STALE"
+
+	defaultCacheMaxEntry = 1 << 20
+)
+
+var cacheableStatuses = map[int]bool{
+	http.StatusOK: true, http.StatusNonAuthoritativeInfo: true, http.StatusNoContent: true,
+	http.StatusMultipleChoices: true, http.StatusMovedPermanently: true, http.StatusNotFound: true,
+	http.StatusMethodNotAllowed: true, http.StatusGone: true, http.StatusRequestURITooLong: true,
+	http.StatusNotImplemented: true, http.StatusPermanentRedirect: true,
+}
+
+type (
+	//CacheStorage interface of response cache storage
+	CacheStorage interface {
+		Get(key string) (*CacheEntry, bool)
+		Set(key string, e *CacheEntry)
+		Delete(key string)
+	}
+
+	//CacheEntry cached response
+	CacheEntry struct {
+		Status int
+		Header http.Header
+		Body   []byte
+		//Vary values of request headers listed in `Vary` of response
+		Vary http.Header
+		//Stored time of receiving of response
+		Stored time.Time
+	}
+
+	//CacheConfig model
+	CacheConfig struct {
+		//MaxEntry max size of cached body, larger responses are not cached
+		MaxEntry int64 `yaml:"max_entry"`
+	}
+)
+
+//Size approximate size of entry in bytes
+func (v *CacheEntry) Size() int64 {
+	size := int64(len(v.Body))
+	for _, h := range []http.Header{v.Header, v.Vary} {
+		for k, vals := range h {
+			size += int64(len(k))
+			for _, val := range vals {
+				size += int64(len(val))
+			}
+		}
+	}
+	return size
+}
+
+//CacheInterceptor private http cache (RFC 9111) of GET responses: freshness by Cache-Control and Expires,
+//revalidation with ETag and Last-Modified, Vary and stale-while-revalidate,
+//single variant of response is stored per url, at most one background revalidation is made per url
+func CacheInterceptor(conf CacheConfig, store CacheStorage) Interceptor {
+	if conf.MaxEntry <= 0 {
+		conf.MaxEntry = defaultCacheMaxEntry
+	}
+	var (
+		inflight = make(map[string]struct{})
+		lock     sync.Mutex
+	)
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			key := r.URL.String()
+			if r.Method != http.MethodGet || len(r.Header.Get("Range")) > 0 {
+				resp, err := next(r)
+				if err == nil && !isSafe(r.Method) && resp.StatusCode < http.StatusBadRequest {
+					store.Delete(key)
+				}
+				return resp, err
+			}
+
+			reqCC := parseCacheControl(r.Header)
+			if reqCC.has("no-store") {
+				return next(r)
+			}
+
+			e, ok := store.Get(key)
+			if ok && !e.matchVary(r.Header) {
+				ok = false
+			}
+			if !ok {
+				return fetchCache(conf, store, key, next, r, nil)
+			}
+
+			now := time.Now()
+			respCC := parseCacheControl(e.Header)
+			age, lifetime := e.age(now), e.lifetime(respCC)
+			fresh := age < lifetime && !respCC.has("no-cache") && !reqCC.has("no-cache")
+			if maxAge, ok := reqCC.seconds("max-age"); ok && age >= maxAge {
+				fresh = false
+			}
+			if fresh {
+				return e.response(r, age, CacheHit), nil
+			}
+
+			if swr, ok := respCC.seconds("stale-while-revalidate"); ok && age < lifetime+swr &&
+				!respCC.has("no-cache") && !reqCC.has("no-cache") && !respCC.has("must-revalidate") {
+				lock.Lock()
+				_, busy := inflight[key]
+				inflight[key] = struct{}{}
+				lock.Unlock()
+				if busy {
+					return e.response(r, age, CacheStale), nil
+				}
+				bg := r.Clone(context.Background())
+				go func() {
+					defer func() {
+						lock.Lock()
+						delete(inflight, key)
+						lock.Unlock()
+					}()
+					if resp, err := fetchCache(conf, store, key, next, bg, e); err == nil {
+						io.Copy(io.Discard, resp.Body) //nolint: errcheck
+						resp.Body.Close()              //nolint: errcheck
+					}
+				}()
+				return e.response(r, age, CacheStale), nil
+			}
+			return fetchCache(conf, store, key, next, r, e)
+		}
+	}
+}
+
+//fetchCache make request (conditional if entry exists) and store response
+func fetchCache(conf CacheConfig, store CacheStorage, key string, next RoundTripFunc, r *http.Request, e *CacheEntry) (*http.Response, error) {
+	if e != nil {
+		r = r.Clone(r.Context())
+		if etag := e.Header.Get("ETag"); len(etag) > 0 {
+			r.Header.Set("If-None-Match", etag)
+		}
+		if lm := e.Header.Get("Last-Modified"); len(lm) > 0 {
+			r.Header.Set("If-Modified-Since", lm)
+		}
+	}
+	resp, err := next(r)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if e != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close() //nolint: errcheck
+		updated := &CacheEntry{Status: e.Status, Header: e.Header.Clone(), Body: e.Body, Vary: e.Vary, Stored: now}
+		//age is counted from receiving of 304, only Age of 304 response is applied
+		updated.Header.Del("Age")
+		for k, vals := range resp.Header {
+			updated.Header[k] = vals
+		}
+		store.Set(key, updated)
+		return updated.response(r, updated.age(now), CacheRevalidated), nil
+	}
+
+	cc := parseCacheControl(resp.Header)
+	if !cacheableStatuses[resp.StatusCode] || cc.has("no-store") || resp.Header.Get("Vary") == "*" {
+		resp.Header.Set(CacheHeader, CacheMiss)
+		return resp, nil
+	}
+	entry := &CacheEntry{Status: resp.StatusCode, Header: resp.Header.Clone(), Stored: now, Vary: make(http.Header)}
+	if entry.lifetime(cc) <= 0 && !cc.has("no-cache") && !cc.has("stale-while-revalidate") &&
+		len(resp.Header.Get("ETag")) == 0 && len(resp.Header.Get("Last-Modified")) == 0 {
+		resp.Header.Set(CacheHeader, CacheMiss)
+		return resp, nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, conf.MaxEntry+1))
+	if err != nil {
+		resp.Body.Close() //nolint: errcheck
+		return nil, err
+	}
+	if int64(len(b)) > conf.MaxEntry {
+		resp.Body = &multiReadCloser{Reader: io.MultiReader(bytes.NewReader(b), resp.Body), Closer: resp.Body}
+		resp.Header.Set(CacheHeader, CacheMiss)
+		return resp, nil
+	}
+	resp.Body.Close() //nolint: errcheck
+
+	for _, name := range resp.Header.Values("Vary") {
+		for _, h := range strings.Split(name, ",") {
+			if h = http.CanonicalHeaderKey(strings.TrimSpace(h)); len(h) > 0 {
+				entry.Vary[h] = r.Header.Values(h)
+			}
+		}
+	}
+	entry.Body = b
+	store.Set(key, entry)
+
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	resp.Header.Set(CacheHeader, CacheMiss)
+	return resp, nil
+}
+
+func (v *CacheEntry) matchVary(h http.Header) bool {
+	for k, vals := range v.Vary {
+		if strings.Join(h.Values(k), ",") != strings.Join(vals, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+//age current age of response
+func (v *CacheEntry) age(now time.Time) time.Duration {
+	age := now.Sub(v.Stored)
+	if n, err := strconv.ParseInt(v.Header.Get("Age"), 10, 64); err == nil && n > 0 {
+		age += time.Duration(n) * time.Second
+	}
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+//lifetime freshness lifetime by max-age, Expires or heuristic by Last-Modified
+func (v *CacheEntry) lifetime(cc cacheControl) time.Duration {
+	if d, ok := cc.seconds("max-age"); ok {
+		return d
+	}
+	date, err := http.ParseTime(v.Header.Get("Date"))
+	if err != nil {
+		date = v.Stored
+	}
+	if exp := v.Header.Get("Expires"); len(exp) > 0 {
+		t, err := http.ParseTime(exp)
+		if err != nil {
+			return 0
+		}
+		return t.Sub(date)
+	}
+	if lm, err := http.ParseTime(v.Header.Get("Last-Modified")); err == nil && v.Status == http.StatusOK {
+		return date.Sub(lm) / 10
+	}
+	return 0
+}
+
+func (v *CacheEntry) response(r *http.Request, age time.Duration, status string) *http.Response {
+	h := v.Header.Clone()
+	h.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	h.Set(CacheHeader, status)
+	return &http.Response{
+		Status:        strconv.Itoa(v.Status) + " " + http.StatusText(v.Status),
+		StatusCode:    v.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(v.Body)),
+		ContentLength: int64(len(v.Body)),
+		Request:       r,
+	}
+}
+
+func isSafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//cacheControl parsed directives of Cache-Control header
+type cacheControl map[string]string
+
+func parseCacheControl(h http.Header) cacheControl {
+	cc := make(cacheControl)
+	for _, line := range h.Values("Cache-Control") {
+		for _, item := range strings.Split(line, ",") {
+			kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+			if len(kv[0]) == 0 {
+				continue
+			}
+			val := ""
+			if len(kv) == 2 {
+				val = strings.Trim(kv[1], `"`)
+			}
+			cc[strings.ToLower(kv[0])] = val
+		}
+	}
+	if len(cc) == 0 && strings.Contains(h.Get("Pragma"), "no-cache") {
+		cc["no-cache"] = ""
+	}
+	return cc
+}
+
+func (v cacheControl) has(key string) bool {
+	_, ok := v[key]
+	return ok
+}
+
+func (v cacheControl) seconds(key string) (time.Duration, bool) {
+	val, ok := v[key]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return time.Duration(n) * time.Second, true
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"container/list"
+	"sync"
+)
+
+var _ CacheStorage = (*MemoryCache)(nil)
+
+type memoryItem struct {
+	key   string
+	entry *CacheEntry
+	size  int64
+}
+
+//MemoryCache in-memory LRU cache storage bounded by size in bytes
+type MemoryCache struct {
+	max   int64
+	size  int64
+	order *list.List
+	items map[string]*list.Element
+	lock  sync.Mutex
+}
+
+//NewMemoryCache init LRU cache storage
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		max:   maxBytes,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+//Get getting entry and marking it as recently used
+func (v *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	el, ok := v.items[key]
+	if !ok {
+		return nil, false
+	}
+	v.order.MoveToFront(el)
+	return el.Value.(*memoryItem).entry, true
+}
+
+//Set adding entry, least recently used entries are removed if size exceeds limit
+func (v *MemoryCache) Set(key string, e *CacheEntry) {
+	size := e.Size() + int64(len(key))
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.remove(key)
+	if size > v.max {
+		return
+	}
+	v.items[key] = v.order.PushFront(&memoryItem{key: key, entry: e, size: size})
+	v.size += size
+	for v.size > v.max {
+		v.remove(v.order.Back().Value.(*memoryItem).key)
+	}
+}
+
+//Delete removing entry
+func (v *MemoryCache) Delete(key string) {
+	v.lock.Lock()
+	v.remove(key)
+	v.lock.Unlock()
+}
+
+//Size current size of entries in bytes
+func (v *MemoryCache) Size() int64 {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.size
+}
+
+func (v *MemoryCache) remove(key string) {
+	el, ok := v.items[key]
+	if !ok {
+		return
+	}
+	v.order.Remove(el)
+	delete(v.items, key)
+	v.size -= el.Value.(*memoryItem).size
+}
@@ -0,0 +1,161 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_CacheInterceptor(t *testing.T) {
+	var calls, revalidated int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&revalidated, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+		case "/swr":
+			w.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write([]byte(strconv.Itoa(int(n)))) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	cli := web.New()
+	cli.Use(web.CacheInterceptor(web.CacheConfig{}, web.NewMemoryCache(1<<20)))
+
+	get := func(path string, h http.Header) (string, string) {
+		resp, err := cli.Do(context.Background(), web.Request{URL: srv.URL + path, Header: h})
+		require.NoError(t, err)
+		b, err := resp.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.Status)
+		return string(b), resp.Header.Get(web.CacheHeader)
+	}
+	check := func(path string, h http.Header, body, status string) {
+		b, s := get(path, h)
+		require.Equal(t, body, b, path)
+		require.Equal(t, status, s, path)
+	}
+
+	check("/fresh", nil, "1", web.CacheMiss)
+	check("/fresh", nil, "1", web.CacheHit)
+	check("/fresh", http.Header{"Cache-Control": {"no-cache"}}, "2", web.CacheMiss)
+
+	check("/etag", nil, "3", web.CacheMiss)
+	check("/etag", nil, "3", web.CacheRevalidated)
+	require.Equal(t, int32(1), atomic.LoadInt32(&revalidated))
+
+	check("/vary", http.Header{"Accept-Language": {"en"}}, "5", web.CacheMiss)
+	check("/vary", http.Header{"Accept-Language": {"en"}}, "5", web.CacheHit)
+	check("/vary", http.Header{"Accept-Language": {"ru"}}, "6", web.CacheMiss)
+
+	check("/swr", nil, "7", web.CacheMiss)
+	check("/swr", nil, "7", web.CacheStale)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 8 }, time.Second, 5*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	check("/swr", nil, "8", web.CacheStale)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 9 }, time.Second, 5*time.Millisecond)
+
+	check("/nostore", nil, "10", web.CacheMiss)
+	check("/nostore", nil, "11", web.CacheMiss)
+
+	_, _, err := cli.Call(http.MethodPost, srv.URL+"/fresh", nil)
+	require.NoError(t, err)
+	check("/fresh", nil, "13", web.CacheMiss)
+}
+
+func TestUnit_MemoryCache(t *testing.T) {
+	c := web.NewMemoryCache(30)
+	entry := func(body string) *web.CacheEntry {
+		return &web.CacheEntry{Status: http.StatusOK, Body: []byte(body)}
+	}
+	c.Set("a", entry("1234567890"))
+	c.Set("b", entry("1234567890"))
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	c.Set("c", entry("1234567890"))
+
+	_, ok = c.Get("b")
+	require.False(t, ok)
+	_, ok = c.Get("a")
+	require.True(t, ok)
+	_, ok = c.Get("c")
+	require.True(t, ok)
+	require.Equal(t, int64(22), c.Size())
+
+	c.Set("d", entry("123456789012345678901234567890"))
+	_, ok = c.Get("d")
+	require.False(t, ok)
+
+	c.Delete("a")
+	require.Equal(t, int64(11), c.Size())
+}
+
+func TestUnit_CacheInterceptorRevalidateOnce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch r.URL.Path {
+		case "/swr":
+			if n > 1 {
+				<-release
+			}
+			w.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+		case "/age":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Age", "30")
+		}
+		w.Write([]byte(strconv.Itoa(int(n)))) //nolint: errcheck
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	cli := web.New()
+	cli.Use(web.CacheInterceptor(web.CacheConfig{}, web.NewMemoryCache(1<<20)))
+	get := func(path string) *web.Response {
+		resp, err := cli.Do(context.Background(), web.Request{URL: srv.URL + path})
+		require.NoError(t, err)
+		_, err = resp.Bytes()
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, web.CacheMiss, get("/swr").Header.Get(web.CacheHeader))
+	for i := 0; i < 10; i++ {
+		require.Equal(t, web.CacheStale, get("/swr").Header.Get(web.CacheHeader))
+	}
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	require.Equal(t, "30", get("/age").Header.Get("Age"))
+	resp := get("/age")
+	require.Equal(t, web.CacheRevalidated, resp.Header.Get(web.CacheHeader))
+	require.Equal(t, "0", resp.Header.Get("Age"))
+}
@@ -2,27 +2,35 @@ package web
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/deweppro/go-errors"
-	"github.com/deweppro/go-http/internal"
 	"github.com/deweppro/go-http/pkg/errs"
 	"github.com/deweppro/go-http/pkg/pool"
 	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/deweppro/go-logger"
 )
 
+const maxPoolPicks = 3
+
 //Client ...
 type Client struct {
 	cli *http.Client
 
-	headers http.Header
-	signer  signature.SignGetter
+	headers  http.Header
+	signer   signature.SignGetter
+	retry    *RetryConfig
+	breaker  *Breaker
+	hedge    *hedger
+	verifier *signature.Storage
 
-	debug  bool
-	writer io.Writer
+	interceptors []Interceptor
+	debug        logger.Logger
 }
 
 func New() *Client {
@@ -48,9 +56,20 @@ func NewCustom(cli *http.Client) *Client {
 	}
 }
 
-//Debug enable logging of responses
+//Debug enable logging of requests to writer (stdout if nil)
+//
+//Deprecated: use Client.Use with LoggingInterceptor
 func (v *Client) Debug(is bool, w io.Writer) {
-	v.debug, v.writer = is, w
+	if !is {
+		v.debug = nil
+		return
+	}
+	log := logger.New()
+	log.SetLevel(logger.LevelInfo)
+	if w != nil {
+		log.SetOutput(w)
+	}
+	v.debug = log
 }
 
 //WithHeaders setting headers
@@ -58,38 +77,228 @@ func (v *Client) WithHeaders(heads http.Header) {
 	v.headers = heads
 }
 
+//WithBreaker setting circuit breaker, requests to hosts with open circuit fail fast with *BreakerOpenError
+func (v *Client) WithBreaker(b *Breaker) {
+	v.breaker = b
+}
+
 //WithAuth sitting auth
 func (v *Client) WithAuth(s signature.SignGetter) {
 	v.signer = s
 }
 
-//Call make request to server
-func (v *Client) Call(method, uri string, body []byte) (int, []byte, error) {
-	req, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(body))
+//Do make request to server
+func (v *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	u, err := req.build(nil)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
+	}
+	return v.execute(ctx, req, false, func([]*url.URL) (*url.URL, func(outcome), error) {
+		return u, func(outcome) {}, nil
+	})
+}
+
+//DoPool make request to pool of server, Request.URL contains path and query only,
+//result of request is reported to pool if it supports tracking (example: pool.Balancer),
+//retries and hedged requests prefer endpoints which were not used yet
+func (v *Client) DoPool(ctx context.Context, p pool.PoolGetter, req Request) (*Response, error) {
+	t, ok := p.(pool.Tracker)
+	if !ok {
+		return v.execute(ctx, req, true, func(used []*url.URL) (*url.URL, func(outcome), error) {
+			var (
+				base *url.URL
+				err  error
+			)
+			for i := 0; i < maxPoolPicks; i++ {
+				if base, err = p.Pool(); err != nil {
+					return nil, nil, errors.Wrap(err, errs.ErrEmptyPool)
+				}
+				if !hasHost(used, base.Host) {
+					break
+				}
+			}
+			u, err := req.build(base)
+			return u, func(outcome) {}, err
+		})
+	}
+
+	tried := make([]*pool.Endpoint, 0, 1)
+	return v.execute(ctx, req, true, func([]*url.URL) (*url.URL, func(outcome), error) {
+		ep, err := t.Next(req.HashKey, tried...)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errs.ErrEmptyPool)
+		}
+		tried = append(tried, ep)
+		u, err := req.build(ep.URL())
+		if err != nil {
+			t.Release(ep)
+			return nil, nil, err
+		}
+		return u, func(res outcome) {
+			if res == outcomeSkipped {
+				t.Release(ep)
+				return
+			}
+			t.Done(ep, res == outcomeSuccess)
+		}, nil
+	})
+}
+
+//outcome result of attempt reported to balancer and circuit breaker
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	//outcomeSkipped request is not sent or canceled, it is not counted as success or failure
+	outcomeSkipped
+)
+
+//target getting url for next attempt and callback for reporting of result
+type target func(used []*url.URL) (*url.URL, func(outcome), error)
+
+func (v *Client) execute(ctx context.Context, req Request, hedge bool, next target) (*Response, error) {
+	method := req.Method
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	attempts := v.retry.attempts(method)
+	if req.Stream {
+		attempts, hedge = 1, false
+		ctx = withStream(ctx)
+	}
+
+	body := func() io.Reader { return req.Body }
+	if req.Body != nil && (attempts > 1 || hedge && v.hedge.enabled(method)) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = func() io.Reader { return bytes.NewReader(b) }
+	}
+
+	start := time.Now()
+	used := make([]*url.URL, 0, 1)
+	for attempt := 0; ; attempt++ {
+		u, done, err := next(used)
+		if err != nil {
+			return nil, err
+		}
+		used = append(used, u)
+
+		var resp *Response
+		if hedge && v.hedge.enabled(method) {
+			resp, err = v.hedged(ctx, method, u, done, req.Header, body, func() (*url.URL, func(outcome), error) {
+				hu, hdone, err := next(used)
+				if err == nil {
+					used = append(used, hu)
+				}
+				return hu, hdone, err
+			})
+		} else {
+			resp, err = v.try(ctx, method, u, done, req.Header, body())
+		}
+		if re, ok := err.(*requestError); ok {
+			return nil, re.err
+		}
+		if attempt+1 >= attempts || !v.retry.retryable(ctx, resp, err) {
+			return resp, err
+		}
+		delay := v.retry.delay(attempt, resp)
+		if v.retry.Budget > 0 && time.Since(start)+delay > v.retry.Budget {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body) //nolint: errcheck
+			resp.Close()                   //nolint: errcheck
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+//try make request to target with circuit breaker check and reporting of result
+func (v *Client) try(ctx context.Context, method string, u *url.URL, done func(outcome), head http.Header, body io.Reader) (*Response, error) {
+	var release func(outcome)
+	if v.breaker != nil {
+		var err error
+		if release, err = v.breaker.allow(u.Host); err != nil {
+			//request is not sent, it is not a failure of endpoint
+			done(outcomeSkipped)
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err := v.send(ctx, method, u, head, body)
+	res := outcomeFailure
+	if err == nil && resp.Status < http.StatusInternalServerError {
+		res = outcomeSuccess
+		v.hedge.observe(u.Host, time.Since(start))
+	}
+	//canceled request (by caller or by winner of hedged requests) or not sent request is not a result of endpoint
+	if _, ok := err.(*requestError); ok || err != nil && ctx.Err() != nil {
+		res = outcomeSkipped
+	}
+	done(res)
+	if release != nil {
+		release(res)
+	}
+	return resp, err
+}
+
+func (v *Client) send(ctx context.Context, method string, u *url.URL, head http.Header, body io.Reader) (*Response, error) {
+	r, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, &requestError{err: err}
 	}
-	req.Header.Set("Connection", "keep-alive")
+	r.Header.Set("Connection", "keep-alive")
 	for k := range v.headers {
-		req.Header.Set(k, v.headers.Get(k))
+		r.Header.Set(k, v.headers.Get(k))
 	}
-	if v.signer != nil {
-		signature.Encode(req.Header, v.signer, body)
+	for k, vals := range head {
+		r.Header[k] = append([]string(nil), vals...)
 	}
-	resp, err := v.cli.Do(req)
+	resp, err := v.roundTrip()(r)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
+	}
+	return &Response{
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   resp.Body,
+	}, nil
+}
+
+func hasHost(list []*url.URL, host string) bool {
+	for _, u := range list {
+		if u.Host == host {
+			return true
+		}
 	}
-	b, err := internal.ReadAll(resp.Body)
-	return resp.StatusCode, b, err
+	return false
+}
+
+//Call make request to server
+func (v *Client) Call(method, uri string, body []byte) (int, []byte, error) {
+	resp, err := v.Do(context.Background(), Request{Method: method, URL: uri, Body: bytes.NewReader(body)})
+	return readResponse(resp, err)
 }
 
 //CallPool make request to pool of server
-func (v *Client) CallPool(pool pool.PoolGetter, method, uri string, body []byte) (int, []byte, error) {
-	url, err := pool.Pool()
+func (v *Client) CallPool(p pool.PoolGetter, method, uri string, body []byte) (int, []byte, error) {
+	resp, err := v.DoPool(context.Background(), p, Request{Method: method, URL: uri, Body: bytes.NewReader(body)})
+	return readResponse(resp, err)
+}
+
+func readResponse(resp *Response, err error) (int, []byte, error) {
 	if err != nil {
-		return 0, nil, errors.Wrap(err, errs.ErrEmptyPool)
+		return 0, nil, err
 	}
-	url.Path = uri
-	return v.Call(method, url.String(), body)
+	b, err := resp.Bytes()
+	return resp.Status, b, err
 }
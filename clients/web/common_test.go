@@ -0,0 +1,93 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ClientDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := internal.ReadAll(r.Body) //nolint: errcheck
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Token", r.Header.Get("X-Token"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(r.URL.RequestURI() + " " + string(b))) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	cli := web.New()
+	resp, err := cli.Do(context.Background(), web.Request{
+		Method: http.MethodPut,
+		URL:    srv.URL + "/users?a=1",
+		Query:  url.Values{"b": {"2"}},
+		Header: http.Header{"X-Token": {"123"}},
+		Body:   strings.NewReader("hello"),
+	})
+	require.NoError(t, err)
+	b, err := resp.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.Status)
+	require.Equal(t, http.MethodPut, resp.Header.Get("X-Method"))
+	require.Equal(t, "123", resp.Header.Get("X-Token"))
+	require.Equal(t, "/users?a=1&b=2 hello", string(b))
+
+	code, b, err := cli.Call(http.MethodDelete, srv.URL+"/users", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, "/users ", string(b))
+
+	code, b, err = cli.CallPool(pool.List{srv.URL}, http.MethodPatch, "/pool", []byte("data"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, "/pool data", string(b))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cli.Do(ctx, web.Request{URL: srv.URL})
+	require.Error(t, err)
+}
+
+func TestUnit_ClientDoPoolHashKey(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name)) //nolint: errcheck
+		}))
+	}
+	srv1, srv2, srv3 := newServer("1"), newServer("2"), newServer("3")
+	defer srv1.Close()
+	defer srv2.Close()
+	defer srv3.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{Strategy: pool.StrategyConsistentHash},
+		pool.List{srv1.URL, srv2.URL, srv3.URL}.Targets()...)
+	require.NoError(t, err)
+
+	cli := web.New()
+	call := func(key string) string {
+		resp, err := cli.DoPool(context.Background(), b, web.Request{URL: "/", HashKey: key})
+		require.NoError(t, err)
+		body, err := resp.Bytes()
+		require.NoError(t, err)
+		return string(body)
+	}
+	used := make(map[string]struct{})
+	for i := 0; i < 20; i++ {
+		key := "user-" + strconv.Itoa(i)
+		expected := call(key)
+		used[expected] = struct{}{}
+		for j := 0; j < 3; j++ {
+			require.Equal(t, expected, call(key))
+		}
+	}
+	require.Greater(t, len(used), 1)
+}
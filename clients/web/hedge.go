@@ -0,0 +1,240 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeRate    = 0.1
+	defaultHedgeSamples = 100
+	minHedgeSamples     = 10
+	hedgeRateWindow     = 1000
+)
+
+//HedgeConfig model
+type HedgeConfig struct {
+	//Percentile of observed latency of endpoint used as hedge delay (0..1, example: 0.95)
+	Percentile float64 `yaml:"percentile"`
+	//Delay fixed hedge delay, used if percentile is not set or there are not enough observations
+	Delay time.Duration `yaml:"delay"`
+	//MaxRate max share of hedged requests (0..1), default 0.1
+	MaxRate float64 `yaml:"max_rate"`
+	//Samples count of latest observations of endpoint latency
+	Samples int `yaml:"samples"`
+	//Methods hedged methods, default: GET, HEAD
+	Methods []string `yaml:"methods"`
+}
+
+//WithHedge enable hedged requests for DoPool and CallPool,
+//second request is sent to another endpoint if first one is not completed after delay
+func (v *Client) WithHedge(conf HedgeConfig) {
+	if conf.MaxRate <= 0 || conf.MaxRate > 1 {
+		conf.MaxRate = defaultHedgeRate
+	}
+	if conf.Samples <= 0 {
+		conf.Samples = defaultHedgeSamples
+	}
+	if len(conf.Methods) == 0 {
+		conf.Methods = []string{http.MethodGet, http.MethodHead}
+	}
+	v.hedge = &hedger{
+		conf:      conf,
+		latencies: make(map[string]*latency),
+	}
+}
+
+//hedger latency tracking and rate limiting of hedged requests
+type hedger struct {
+	conf      HedgeConfig
+	latencies map[string]*latency
+	total     int
+	hedged    int
+	lock      sync.Mutex
+}
+
+func (v *hedger) enabled(method string) bool {
+	if v == nil {
+		return false
+	}
+	for _, m := range v.conf.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+//observe add latency of successful request to endpoint
+func (v *hedger) observe(host string, d time.Duration) {
+	if v == nil {
+		return
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	l, ok := v.latencies[host]
+	if !ok {
+		l = &latency{list: make([]time.Duration, 0, v.conf.Samples)}
+		v.latencies[host] = l
+	}
+	l.add(d, v.conf.Samples)
+}
+
+//delay getting hedge delay for endpoint
+func (v *hedger) delay(host string) (time.Duration, bool) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.total++
+	if v.total >= hedgeRateWindow {
+		v.total, v.hedged = v.total/2, v.hedged/2
+	}
+	if v.conf.Percentile > 0 {
+		if l, ok := v.latencies[host]; ok && len(l.list) >= minHedgeSamples {
+			return l.percentile(v.conf.Percentile), true
+		}
+	}
+	return v.conf.Delay, v.conf.Delay > 0
+}
+
+//acquire checking of hedge rate cap
+func (v *hedger) acquire() bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if float64(v.hedged+1) > v.conf.MaxRate*float64(v.total) {
+		return false
+	}
+	v.hedged++
+	return true
+}
+
+//latency ring of latest observations
+type latency struct {
+	list []time.Duration
+	i    int
+}
+
+func (v *latency) add(d time.Duration, size int) {
+	if len(v.list) < size {
+		v.list = append(v.list, d)
+		return
+	}
+	v.list[v.i] = d
+	v.i = (v.i + 1) % size
+}
+
+func (v *latency) percentile(p float64) time.Duration {
+	list := append(make([]time.Duration, 0, len(v.list)), v.list...)
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	i := int(p * float64(len(list)))
+	if i >= len(list) {
+		i = len(list) - 1
+	}
+	return list[i]
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type hedgeResult struct {
+	leg    int
+	resp   *Response
+	err    error
+	cancel context.CancelFunc
+}
+
+//cancelBody canceling of request context on close of response body
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (v *cancelBody) Close() error {
+	defer v.cancel()
+	return v.ReadCloser.Close()
+}
+
+func (v hedgeResult) response() (*Response, error) {
+	if v.resp != nil {
+		v.resp.Body = &cancelBody{ReadCloser: v.resp.Body, cancel: v.cancel}
+	} else {
+		v.cancel()
+	}
+	return v.resp, v.err
+}
+
+//hedged make request and hedge request to next endpoint after delay, first successful response is returned
+func (v *Client) hedged(ctx context.Context, method string, u *url.URL, done func(outcome), head http.Header,
+	body func() io.Reader, next func() (*url.URL, func(outcome), error)) (*Response, error) {
+
+	delay, ok := v.hedge.delay(u.Host)
+	if !ok {
+		return v.try(ctx, method, u, done, head, body())
+	}
+
+	results := make(chan hedgeResult, 2)
+	cancels := make([]context.CancelFunc, 0, 2)
+	launch := func(u *url.URL, done func(outcome)) {
+		lctx, cancel := context.WithCancel(ctx)
+		leg := len(cancels)
+		cancels = append(cancels, cancel)
+		b := body()
+		go func() {
+			resp, err := v.try(lctx, method, u, done, head, b)
+			results <- hedgeResult{leg: leg, resp: resp, err: err, cancel: cancel}
+		}()
+	}
+	launch(u, done)
+	pending := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	timerC := timer.C
+
+	for {
+		select {
+		case <-timerC:
+			timerC = nil
+			hu, hdone, err := next()
+			if err != nil {
+				continue
+			}
+			//pool has no other endpoint, hedge to the same server doubles its load
+			if hu.Host == u.Host || !v.hedge.acquire() {
+				hdone(outcomeSkipped)
+				continue
+			}
+			launch(hu, hdone)
+			pending++
+
+		case r := <-results:
+			pending--
+			if r.err == nil && r.resp.Status < http.StatusInternalServerError || pending == 0 {
+				for leg, cancel := range cancels {
+					if leg != r.leg {
+						cancel()
+					}
+				}
+				go drainHedge(results, pending)
+				return r.response()
+			}
+			if r.resp != nil {
+				r.resp.Close() //nolint: errcheck
+			}
+			r.cancel()
+		}
+	}
+}
+
+func drainHedge(results chan hedgeResult, pending int) {
+	for i := 0; i < pending; i++ {
+		r := <-results
+		if r.resp != nil {
+			r.resp.Close() //nolint: errcheck
+		}
+		r.cancel()
+	}
+}
@@ -0,0 +1,86 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ClientHedge(t *testing.T) {
+	var canceled, fast int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			atomic.AddInt32(&canceled, 1)
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte("slow")) //nolint: errcheck
+	}))
+	defer slow.Close()
+	quick := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fast, 1)
+		w.Write([]byte("fast")) //nolint: errcheck
+	}))
+	defer quick.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{slow.URL, quick.URL}.Targets()...)
+	require.NoError(t, err)
+
+	cli := web.New()
+	cli.WithHedge(web.HedgeConfig{Delay: 20 * time.Millisecond, MaxRate: 1})
+
+	start := time.Now()
+	code, body, err := cli.CallPool(b, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "fast", string(body))
+	require.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	require.Equal(t, int32(1), atomic.LoadInt32(&fast))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&canceled) == 1 }, time.Second, 10*time.Millisecond)
+
+	code, body, err = cli.CallPool(b, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "fast", string(body))
+	require.Equal(t, int32(2), atomic.LoadInt32(&fast))
+
+	cli = web.New()
+	cli.WithHedge(web.HedgeConfig{Delay: 20 * time.Millisecond, MaxRate: 0.01})
+	code, body, err = cli.CallPool(b, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "slow", string(body))
+}
+
+func TestUnit_ClientHedgeSameHost(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("ok")) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{srv.URL}.Targets()...)
+	require.NoError(t, err)
+
+	cli := web.New()
+	cli.WithHedge(web.HedgeConfig{Delay: 10 * time.Millisecond, MaxRate: 1})
+	for i := 0; i < 5; i++ {
+		code, _, err := cli.CallPool(b, http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	}
+	code, _, err := cli.CallPool(pool.List{srv.URL}, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
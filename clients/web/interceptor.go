@@ -0,0 +1,126 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/deweppro/go-logger"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+type (
+	//RoundTripFunc interface of request execution
+	RoundTripFunc func(*http.Request) (*http.Response, error)
+	//Interceptor interface of client middleware
+	Interceptor func(next RoundTripFunc) RoundTripFunc
+)
+
+//Use add interceptors, they wrap every attempt of request in the order of adding
+func (v *Client) Use(interceptors ...Interceptor) {
+	v.interceptors = append(v.interceptors, interceptors...)
+}
+
+func (v *Client) roundTrip() RoundTripFunc {
+	next := RoundTripFunc(v.cli.Do)
+	if v.verifier != nil {
+		next = SignatureVerifyInterceptor(v.verifier)(next)
+	}
+	if v.signer != nil {
+		next = SignatureInterceptor(v.signer)(next)
+	}
+	for i := len(v.interceptors) - 1; i >= 0; i-- {
+		next = v.interceptors[i](next)
+	}
+	if v.debug != nil {
+		next = LoggingInterceptor(v.debug, "")(next)
+	}
+	return next
+}
+
+//SignatureInterceptor signing of request body with `Signature` header
+func SignatureInterceptor(s signature.SignGetter) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			var body []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				b, err := io.ReadAll(r.Body)
+				r.Body.Close() //nolint: errcheck
+				if err != nil {
+					return nil, err
+				}
+				body = b
+				r.Body = io.NopCloser(bytes.NewReader(b))
+			}
+			signature.Encode(r.Header, s, body)
+			return next(r)
+		}
+	}
+}
+
+//WithRequestID setting request id to context for propagation by RequestIDInterceptor
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+//RequestID getting request id from context
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && len(id) > 0
+}
+
+//RequestIDInterceptor setting request id header from context or new one if it is not set
+func RequestIDInterceptor(header string) Interceptor {
+	if len(header) == 0 {
+		header = RequestIDHeader
+	}
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if len(r.Header.Get(header)) == 0 {
+				id, ok := RequestID(r.Context())
+				if !ok {
+					id = uuid.NewString()
+				}
+				r.Header.Set(header, id)
+			}
+			return next(r)
+		}
+	}
+}
+
+//LoggingInterceptor logging of requests without query and credentials of URL,
+//request id is taken from header (RequestIDHeader if empty)
+func LoggingInterceptor(log logger.Logger, header string) Interceptor {
+	if len(header) == 0 {
+		header = RequestIDHeader
+	}
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(r)
+			fields := logger.Fields{
+				"method":   r.Method,
+				"url":      r.URL.Scheme + "://" + r.URL.Host + r.URL.EscapedPath(),
+				"duration": time.Since(start).String(),
+			}
+			if id := r.Header.Get(header); len(id) > 0 {
+				fields["request_id"] = id
+			}
+			if err != nil {
+				fields["err"] = err.Error()
+				log.WithFields(fields).Errorf("http client request")
+				return resp, err
+			}
+			fields["status"] = resp.StatusCode
+			log.WithFields(fields).Infof("http client request")
+			return resp, nil
+		}
+	}
+}
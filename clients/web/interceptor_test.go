@@ -0,0 +1,98 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/deweppro/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+type syncBuffer struct {
+	buf bytes.Buffer
+	mux sync.Mutex
+}
+
+func (v *syncBuffer) Write(b []byte) (int, error) {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	return v.buf.Write(b)
+}
+
+func (v *syncBuffer) String() string {
+	v.mux.Lock()
+	defer v.mux.Unlock()
+	return v.buf.String()
+}
+
+func TestUnit_ClientInterceptors(t *testing.T) {
+	s := signature.NewSHA256("1", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := internal.ReadAll(r.Body) //nolint: errcheck
+		data, err := signature.Decode(r.Header)
+		if err != nil || !s.Validate(b, data.Hash) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+		w.Header().Set("X-Order", r.Header.Get("X-Order"))
+	}))
+	defer srv.Close()
+
+	out := &syncBuffer{}
+	log := logger.New()
+	log.SetOutput(out)
+	log.SetLevel(logger.LevelInfo)
+
+	order := func(name string) web.Interceptor {
+		return func(next web.RoundTripFunc) web.RoundTripFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				r.Header.Add("X-Order", name)
+				return next(r)
+			}
+		}
+	}
+
+	cli := web.New()
+	cli.WithAuth(s)
+	cli.Use(order("a"), web.RequestIDInterceptor(""), web.LoggingInterceptor(log, ""), order("b"))
+
+	resp, err := cli.Do(web.WithRequestID(context.Background(), "req-1"),
+		web.Request{Method: http.MethodPost, URL: srv.URL, Body: strings.NewReader("hello")})
+	require.NoError(t, err)
+	require.NoError(t, resp.Close())
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Equal(t, "req-1", resp.Header.Get("X-Request-Id"))
+	require.Equal(t, "a", resp.Header.Get("X-Order"))
+
+	resp, err = cli.Do(context.Background(), web.Request{URL: srv.URL + "/path?token=secret"})
+	require.NoError(t, err)
+	require.NoError(t, resp.Close())
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Len(t, resp.Header.Get("X-Request-Id"), 36)
+
+	log.Close()
+	require.Contains(t, out.String(), `"request_id":"req-1"`)
+	require.Contains(t, out.String(), `"status":200`)
+	require.Contains(t, out.String(), srv.URL+"/path")
+	require.NotContains(t, out.String(), "secret")
+
+	debug := &syncBuffer{}
+	cli = web.New()
+	cli.Debug(true, debug)
+	resp, err = cli.Do(context.Background(), web.Request{URL: srv.URL})
+	require.NoError(t, err)
+	require.NoError(t, resp.Close())
+	require.Eventually(t, func() bool {
+		return strings.Contains(debug.String(), `"msg":"http client request"`)
+	}, time.Second, 10*time.Millisecond)
+}
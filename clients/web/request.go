@@ -0,0 +1,68 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/deweppro/go-http/internal"
+)
+
+//Request model of client request
+type Request struct {
+	//Method of request, GET if empty
+	Method string
+	//URL of request, path only for requests to pool
+	URL    string
+	Query  url.Values
+	Header http.Header
+	Body   io.Reader
+	//Stream body is sent without buffering, request is not retried or hedged
+	Stream bool
+	//HashKey key of endpoint selection for consistent-hash strategy of pool.Balancer
+	HashKey string
+}
+
+//Response model of client response, body must be closed
+type Response struct {
+	Status int
+	Header http.Header
+	Body   io.ReadCloser
+}
+
+//Bytes reading and closing body
+func (v *Response) Bytes() ([]byte, error) {
+	return internal.ReadAll(v.Body)
+}
+
+//Close closing body
+func (v *Response) Close() error {
+	return v.Body.Close()
+}
+
+func (v Request) build(base *url.URL) (*url.URL, error) {
+	var (
+		u   *url.URL
+		err error
+	)
+	if base != nil {
+		u = base
+		ref, err := url.Parse(v.URL)
+		if err != nil {
+			return nil, err
+		}
+		u.Path, u.RawQuery = ref.Path, ref.RawQuery
+	} else if u, err = url.Parse(v.URL); err != nil {
+		return nil, err
+	}
+	if len(v.Query) > 0 {
+		q := u.Query()
+		for k, vals := range v.Query {
+			for _, val := range vals {
+				q.Add(k, val)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u, nil
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+var (
+	defaultRetryStatuses = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+	defaultRetryMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete, http.MethodTrace,
+	}
+)
+
+//RetryConfig model
+type RetryConfig struct {
+	//MaxAttempts count of attempts including first one
+	MaxAttempts int `yaml:"max_attempts"`
+	//BaseDelay of exponential backoff
+	BaseDelay time.Duration `yaml:"base_delay"`
+	//MaxDelay max delay between attempts
+	MaxDelay time.Duration `yaml:"max_delay"`
+	//Budget total time for all attempts, no limit if zero
+	Budget time.Duration `yaml:"budget"`
+	//Statuses retryable status codes, default: 429, 502, 503, 504
+	Statuses []int `yaml:"statuses"`
+	//Methods retryable methods, default: idempotent methods
+	Methods []string `yaml:"methods"`
+}
+
+//WithRetry setting retry policy
+func (v *Client) WithRetry(conf RetryConfig) {
+	if conf.BaseDelay <= 0 {
+		conf.BaseDelay = defaultRetryBaseDelay
+	}
+	if conf.MaxDelay <= 0 {
+		conf.MaxDelay = defaultRetryMaxDelay
+	}
+	if len(conf.Statuses) == 0 {
+		conf.Statuses = defaultRetryStatuses
+	}
+	if len(conf.Methods) == 0 {
+		conf.Methods = defaultRetryMethods
+	}
+	v.retry = &conf
+}
+
+func (v *RetryConfig) attempts(method string) int {
+	if v == nil || v.MaxAttempts <= 1 {
+		return 1
+	}
+	for _, m := range v.Methods {
+		if m == method {
+			return v.MaxAttempts
+		}
+	}
+	return 1
+}
+
+func (v *RetryConfig) retryable(ctx context.Context, resp *Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var re *requestError
+		return !errors.Is(err, context.Canceled) && !errors.As(err, &re)
+	}
+	for _, code := range v.Statuses {
+		if code == resp.Status {
+			return true
+		}
+	}
+	return false
+}
+
+//delay exponential backoff with full jitter or Retry-After header value, both are limited by MaxDelay
+func (v *RetryConfig) delay(attempt int, resp *Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if d > v.MaxDelay {
+				d = v.MaxDelay
+			}
+			return d
+		}
+	}
+	max := v.MaxDelay
+	if attempt < 32 {
+		if d := v.BaseDelay << uint(attempt); d > 0 && d < max {
+			max = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+func retryAfter(h string) (time.Duration, bool) {
+	if len(h) == 0 {
+		return 0, false
+	}
+	if n, err := strconv.Atoi(h); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second, true
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+//requestError error of building request (invalid method, URL or body), it is not retried
+type requestError struct {
+	err error
+}
+
+func (v *requestError) Error() string {
+	return v.err.Error()
+}
+
+func (v *requestError) Unwrap() error {
+	return v.err
+}
@@ -0,0 +1,101 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ClientRetry(t *testing.T) {
+	var calls, limited int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := internal.ReadAll(r.Body) //nolint: errcheck
+		if atomic.LoadInt32(&limited) == 1 {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(b) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	cli := web.New()
+	cli.WithRetry(web.RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	resp, err := cli.Do(context.Background(), web.Request{Method: http.MethodPut, URL: srv.URL, Body: strings.NewReader("hello")})
+	require.NoError(t, err)
+	b, err := resp.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Equal(t, "hello", string(b))
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	code, _, err := cli.Call(http.MethodPost, srv.URL, []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	cli.WithRetry(web.RetryConfig{MaxAttempts: 5, BaseDelay: time.Second, Budget: 100 * time.Millisecond})
+	atomic.StoreInt32(&limited, 1)
+	code, _, err = cli.Call(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, code)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	cli.WithRetry(web.RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond})
+	start := time.Now()
+	code, _, err = cli.Call(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, code)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = cli.Do(context.Background(), web.Request{Method: "BAD METHOD", URL: srv.URL})
+	require.Error(t, err)
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+}
+
+func TestUnit_ClientRetryPool(t *testing.T) {
+	var bad, good int32
+	srv1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&bad, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv1.Close()
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&good, 1)
+	}))
+	defer srv2.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{srv1.URL, srv2.URL}.Targets()...)
+	require.NoError(t, err)
+
+	cli := web.New()
+	cli.WithRetry(web.RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	for i := 0; i < 4; i++ {
+		code, _, err := cli.CallPool(b, http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	}
+	require.Equal(t, int32(4), atomic.LoadInt32(&good))
+	require.Equal(t, int32(4), atomic.LoadInt32(&bad))
+}
@@ -0,0 +1,289 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+const maxDownloadResumes = 5
+
+//ProgressFunc callback of transfer progress, total is -1 if size is unknown
+type ProgressFunc func(done, total int64)
+
+//ProgressReader reader with progress callback
+type ProgressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	call  ProgressFunc
+}
+
+//NewProgressReader init reader with progress callback
+func NewProgressReader(r io.Reader, total int64, call ProgressFunc) *ProgressReader {
+	return &ProgressReader{r: r, total: total, call: call}
+}
+
+func (v *ProgressReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	if n > 0 {
+		v.done += int64(n)
+		v.call(v.done, v.total)
+	}
+	return n, err
+}
+
+//Close closing underlying reader if it is closer
+func (v *ProgressReader) Close() error {
+	if c, ok := v.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type multipartItem struct {
+	field       string
+	filename    string
+	contentType string
+	value       string
+	r           io.Reader
+}
+
+//Multipart builder of multipart/form-data body, files are streamed without buffering
+type Multipart struct {
+	items []multipartItem
+}
+
+//NewMultipart init multipart builder
+func NewMultipart() *Multipart {
+	return &Multipart{items: make([]multipartItem, 0, 2)}
+}
+
+//Field add form field
+func (v *Multipart) Field(name, value string) *Multipart {
+	v.items = append(v.items, multipartItem{field: name, value: value})
+	return v
+}
+
+//File add file with application/octet-stream content type
+func (v *Multipart) File(field, filename string, r io.Reader) *Multipart {
+	return v.FileWithType(field, filename, "application/octet-stream", r)
+}
+
+//FileWithType add file with custom content type
+func (v *Multipart) FileWithType(field, filename, contentType string, r io.Reader) *Multipart {
+	v.items = append(v.items, multipartItem{field: field, filename: filename, contentType: contentType, r: r})
+	return v
+}
+
+//Reader getting body reader and content type, body is written in background via pipe,
+//reader must be closed if it is not read to the end
+func (v *Multipart) Reader() (*io.PipeReader, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(v.write(mw)) //nolint: errcheck
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+func (v *Multipart) write(mw *multipart.Writer) error {
+	for _, item := range v.items {
+		if item.r == nil {
+			if err := mw.WriteField(item.field, item.value); err != nil {
+				return err
+			}
+			continue
+		}
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(item.field), escapeQuotes(item.filename)))
+		h.Set("Content-Type", item.contentType)
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(w, item.r); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+//Upload streaming multipart body to server
+func (v *Client) Upload(ctx context.Context, method, uri string, m *Multipart, progress ProgressFunc) (*Response, error) {
+	pr, contentType := m.Reader()
+	var body io.Reader = pr
+	if progress != nil {
+		body = NewProgressReader(pr, -1, progress)
+	}
+	resp, err := v.Do(ctx, Request{
+		Method: method,
+		URL:    uri,
+		Header: http.Header{"Content-Type": {contentType}},
+		Body:   body,
+		Stream: true,
+	})
+	if err != nil {
+		//request is not sent or interrupted, writer of body is stopped
+		pr.CloseWithError(err) //nolint: errcheck
+	}
+	return resp, err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//Download streaming response body to writer, download is started from offset (example: size of partial file),
+//interrupted download is resumed with `Range` and `If-Range` requests (by strong ETag or Last-Modified),
+//download is failed with errs.ErrResourceChanged if resource is changed or has no validator for resume,
+//count of written bytes is returned
+func (v *Client) Download(ctx context.Context, uri string, w io.Writer, offset int64, progress ProgressFunc) (int64, error) {
+	var (
+		written   int64
+		validator string
+		total     int64 = -1
+		lastErr   error
+	)
+	//body is not buffered for validation of signature
+	sctx := withStream(ctx)
+	for i := 0; i <= maxDownloadResumes; i++ {
+		pos := offset + written
+		head := http.Header{}
+		validated := false
+		if pos > 0 {
+			//received bytes can not be continued without check that resource is not changed
+			if written > 0 && len(validator) == 0 {
+				return written, errors.WrapMessage(errs.ErrResourceChanged, "no validator to resume %s", uri)
+			}
+			head.Set("Range", "bytes="+strconv.FormatInt(pos, 10)+"-")
+			if len(validator) > 0 {
+				head.Set("If-Range", validator)
+				validated = true
+			}
+		}
+		resp, err := v.Do(sctx, Request{Method: http.MethodGet, URL: uri, Header: head})
+		if err != nil {
+			if ctx.Err() != nil {
+				return written, err
+			}
+			lastErr = err
+			continue
+		}
+
+		switch resp.Status {
+		case http.StatusPartialContent:
+			if start := contentStart(resp); start != pos {
+				resp.Close() //nolint: errcheck
+				return written, errors.WrapMessage(errs.ErrInvalidContentRange, "expected start %d, got %d", pos, start)
+			}
+		case http.StatusOK:
+			//resource is changed since previous part, received bytes are not valid
+			if validated {
+				resp.Close() //nolint: errcheck
+				return written, errors.WrapMessage(errs.ErrResourceChanged, "validator %s", validator)
+			}
+			//range is not supported, skipping of received bytes
+			if pos > 0 {
+				if _, err = io.CopyN(io.Discard, resp.Body, pos); err != nil {
+					resp.Close() //nolint: errcheck
+					lastErr = err
+					continue
+				}
+			}
+		case http.StatusRequestedRangeNotSatisfiable:
+			resp.Close() //nolint: errcheck
+			if pos > 0 && (total < 0 || pos >= total) {
+				return written, nil
+			}
+			return written, &HTTPError{Status: resp.Status, Header: resp.Header}
+		default:
+			b, _ := internal.ReadAll(resp.Body) //nolint: errcheck
+			return written, newHTTPError(resp, b, jsonCodec)
+		}
+
+		if len(validator) == 0 {
+			validator = rangeValidator(resp.Header)
+		}
+		if t := contentTotal(resp, pos); t >= 0 {
+			total = t
+		}
+
+		var body io.Reader = resp.Body
+		if progress != nil {
+			body = &ProgressReader{r: body, done: pos, total: total, call: progress}
+		}
+		n, err := io.Copy(w, body)
+		resp.Close() //nolint: errcheck
+		written += n
+		if err == nil && (total < 0 || offset+written >= total) {
+			return written, nil
+		}
+		if ctx.Err() != nil {
+			return written, ctx.Err()
+		}
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		lastErr = err
+	}
+	return written, lastErr
+}
+
+//rangeValidator getting validator for If-Range: strong ETag or Last-Modified (weak etag can not be used)
+func rangeValidator(h http.Header) string {
+	if etag := h.Get("ETag"); len(etag) > 0 && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return h.Get("Last-Modified")
+}
+
+//contentStart getting first byte position from Content-Range, -1 if it is missing or invalid
+func contentStart(resp *Response) int64 {
+	cr := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	i := strings.IndexByte(cr, '-')
+	if i < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(cr[:i], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+//contentTotal getting full size of content from Content-Range or Content-Length
+func contentTotal(resp *Response, pos int64) int64 {
+	if cr := resp.Header.Get("Content-Range"); len(cr) > 0 {
+		if i := strings.LastIndexByte(cr, '/'); i >= 0 {
+			if n, err := strconv.ParseInt(cr[i+1:], 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	if cl := resp.Header.Get("Content-Length"); len(cl) > 0 {
+		if n, err := strconv.ParseInt(cl, 10, 64); err == nil {
+			if resp.Status == http.StatusPartialContent {
+				return pos + n
+			}
+			return n
+		}
+	}
+	return -1
+}
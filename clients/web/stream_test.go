@@ -0,0 +1,177 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ClientUpload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		f, fh, err := r.FormFile("file")
+		require.NoError(t, err)
+		b, _ := internal.ReadAll(f) //nolint: errcheck
+		w.Write([]byte(r.FormValue("name") + ":" + fh.Filename + ":" + string(b))) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	var progress, total int64
+	m := web.NewMultipart().
+		Field("name", "report").
+		File("file", "data.txt", strings.NewReader(strings.Repeat("a", 1000)))
+	resp, err := web.New().Upload(context.Background(), http.MethodPost, srv.URL, m, func(done, t int64) {
+		atomic.StoreInt64(&progress, done)
+		atomic.StoreInt64(&total, t)
+	})
+	require.NoError(t, err)
+	b, err := resp.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, "report:data.txt:"+strings.Repeat("a", 1000), string(b))
+	require.Greater(t, atomic.LoadInt64(&progress), int64(1000))
+	require.Equal(t, int64(-1), atomic.LoadInt64(&total))
+}
+
+func TestUnit_ClientDownload(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 100))
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//first response is interrupted in the middle
+		call := atomic.AddInt32(&calls, 1)
+		if call == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", "1000")
+			w.Write(data[:300]) //nolint: errcheck
+			if hj, ok := w.(http.Hijacker); ok {
+				w.(http.Flusher).Flush()
+				conn, _, _ := hj.Hijack() //nolint: errcheck
+				conn.Close()              //nolint: errcheck
+			}
+			return
+		}
+		if call == 2 && r.Header.Get("If-Range") != `"v1"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "data", time.Now(), bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	out := &bytes.Buffer{}
+	var last, total int64
+	n, err := web.New().Download(context.Background(), srv.URL, out, 0, func(d, t int64) {
+		last, total = d, t
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), n)
+	require.Equal(t, data, out.Bytes())
+	require.Equal(t, int64(1000), last)
+	require.Equal(t, int64(1000), total)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	out.Reset()
+	n, err = web.New().Download(context.Background(), srv.URL, out, 900, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), n)
+	require.Equal(t, data[900:], out.Bytes())
+
+	out.Reset()
+	n, err = web.New().Download(context.Background(), srv.URL, out, 1000, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), n)
+}
+
+func TestUnit_ClientDownloadChanged(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 100))
+	modified := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls int32
+	var etag, lastModified, ifRange, contentRange atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("ETag", etag.Load().(string))
+			w.Header().Set("Last-Modified", lastModified.Load().(string))
+			w.Header().Set("Content-Length", "1000")
+			w.Write(data[:300]) //nolint: errcheck
+			w.(http.Flusher).Flush()
+			conn, _, _ := w.(http.Hijacker).Hijack() //nolint: errcheck
+			conn.Close()                               //nolint: errcheck
+			return
+		}
+		ifRange.Store(r.Header.Get("If-Range"))
+		if cr := contentRange.Load().(string); len(cr) > 0 {
+			w.Header().Set("Content-Range", cr)
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(data) //nolint: errcheck
+			return
+		}
+		//content is changed, If-Range does not match and full content is sent
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "data", modified.Add(time.Hour), bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	download := func(tag, lm, cr string) (int64, error) {
+		atomic.StoreInt32(&calls, 0)
+		etag.Store(tag)
+		lastModified.Store(lm)
+		contentRange.Store(cr)
+		ifRange.Store("")
+		return web.New().Download(context.Background(), srv.URL, &bytes.Buffer{}, 0, nil)
+	}
+
+	n, err := download(`"v1"`, "", "")
+	require.True(t, errors.Is(err, errs.ErrResourceChanged))
+	require.Equal(t, int64(300), n)
+	require.Equal(t, `"v1"`, ifRange.Load().(string))
+
+	//Last-Modified is used in If-Range if etag is weak or missing
+	lm := modified.Format(http.TimeFormat)
+	n, err = download(`W/"v1"`, lm, "")
+	require.True(t, errors.Is(err, errs.ErrResourceChanged))
+	require.Equal(t, int64(300), n)
+	require.Equal(t, lm, ifRange.Load().(string))
+
+	//download without validator is not resumed
+	n, err = download(`W/"v1"`, "", "")
+	require.True(t, errors.Is(err, errs.ErrResourceChanged))
+	require.Equal(t, int64(300), n)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	//partial content is started not from requested position
+	n, err = download(`"v1"`, "", "bytes 0-999/1000")
+	require.True(t, errors.Is(err, errs.ErrInvalidContentRange))
+	require.Equal(t, int64(300), n)
+}
+
+func TestUnit_ClientUploadFail(t *testing.T) {
+	pr, pw := io.Pipe()
+	require.NoError(t, web.NewProgressReader(pr, -1, func(int64, int64) {}).Close())
+	_, err := pw.Write([]byte("a"))
+	require.True(t, errors.Is(err, io.ErrClosedPipe))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	before := runtime.NumGoroutine()
+	m := web.NewMultipart().File("file", "data.txt", strings.NewReader(strings.Repeat("a", 1<<20)))
+	_, err = web.New().Upload(context.Background(), http.MethodPost, "http://"+addr, m, func(int64, int64) {})
+	require.Error(t, err)
+	require.Eventually(t, func() bool { return runtime.NumGoroutine() <= before }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeXML  = "application/xml"
+)
+
+type codec struct {
+	contentType string
+	marshal     func(interface{}) ([]byte, error)
+	unmarshal   func([]byte, interface{}) error
+}
+
+var (
+	jsonCodec = codec{contentType: contentTypeJSON, marshal: json.Marshal, unmarshal: json.Unmarshal}
+	xmlCodec  = codec{contentType: contentTypeXML, marshal: xml.Marshal, unmarshal: xml.Unmarshal}
+)
+
+//Problem model of RFC 7807 problem details
+type Problem struct {
+	Type     string `json:"type" xml:"type"`
+	Title    string `json:"title" xml:"title"`
+	Status   int    `json:"status" xml:"status"`
+	Detail   string `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string `json:"instance,omitempty" xml:"instance,omitempty"`
+}
+
+//HTTPError error of response with non-2xx status
+type HTTPError struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	//Problem decoded body if response is application/problem+json or application/problem+xml
+	Problem *Problem
+	//codec requested by caller, it is used if response has no json or xml content type
+	codec codec
+}
+
+func (v *HTTPError) Error() string {
+	msg := fmt.Sprintf("http error: %d %s", v.Status, http.StatusText(v.Status))
+	if v.Problem != nil {
+		if len(v.Problem.Title) > 0 {
+			msg += ": " + v.Problem.Title
+		}
+		if len(v.Problem.Detail) > 0 {
+			msg += ": " + v.Problem.Detail
+		}
+	}
+	return msg
+}
+
+//Decode unmarshal body of error response to custom error model (json or xml by content type,
+//requested format if content type is unknown)
+func (v *HTTPError) Decode(out interface{}) error {
+	return codecOf(v.Header, v.codec).unmarshal(v.Body, out)
+}
+
+//codecOf getting codec by content type of response, def is used for other content types
+func codecOf(h http.Header, def codec) codec {
+	ct := h.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, "xml"):
+		return xmlCodec
+	case strings.Contains(ct, "json"):
+		return jsonCodec
+	case def.unmarshal != nil:
+		return def
+	default:
+		return jsonCodec
+	}
+}
+
+//JSON make request with json body and decoding of json response to out (if not nil),
+//*HTTPError is returned for non-2xx responses
+func (v *Client) JSON(ctx context.Context, method, uri string, in, out interface{}) error {
+	return v.typed(ctx, jsonCodec, method, uri, in, out)
+}
+
+//XML make request with xml body and decoding of xml response to out (if not nil),
+//*HTTPError is returned for non-2xx responses
+func (v *Client) XML(ctx context.Context, method, uri string, in, out interface{}) error {
+	return v.typed(ctx, xmlCodec, method, uri, in, out)
+}
+
+func (v *Client) typed(ctx context.Context, c codec, method, uri string, in, out interface{}) error {
+	req := Request{
+		Method: method,
+		URL:    uri,
+		Header: http.Header{"Accept": {c.contentType}},
+	}
+	if in != nil {
+		b, err := c.marshal(in)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", c.contentType+"; charset=utf-8")
+		req.Body = bytes.NewReader(b)
+	}
+	resp, err := v.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	b, err := resp.Bytes()
+	if err != nil {
+		return err
+	}
+	if resp.Status < 200 || resp.Status >= 300 {
+		return newHTTPError(resp, b, c)
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	return codecOf(resp.Header, c).unmarshal(b, out)
+}
+
+func newHTTPError(resp *Response, b []byte, c codec) *HTTPError {
+	e := &HTTPError{Status: resp.Status, Header: resp.Header, Body: b, codec: c}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+") {
+		p := &Problem{}
+		if err := codecOf(resp.Header, c).unmarshal(b, p); err == nil {
+			e.Problem = p
+		}
+	}
+	return e
+}
@@ -0,0 +1,79 @@
+package web_test
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/internal"
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	XMLName xml.Name `json:"-" xml:"user"`
+	ID      int      `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name"`
+}
+
+func TestUnit_ClientJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			b, _ := internal.ReadAll(r.Body) //nolint: errcheck
+			w.Header().Set("Content-Type", r.Header.Get("Accept"))
+			w.Write(b) //nolint: errcheck
+		case "/plain":
+			b, _ := internal.ReadAll(r.Body) //nolint: errcheck
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write(b) //nolint: errcheck
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"type":"about:blank","title":"Forbidden","status":403,"detail":"no access"}`)) //nolint: errcheck
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":42,"message":"bad"}`)) //nolint: errcheck
+		}
+	}))
+	defer srv.Close()
+
+	cli := web.New()
+	ctx := context.Background()
+
+	var out testUser
+	require.NoError(t, cli.JSON(ctx, http.MethodPost, srv.URL+"/user", testUser{ID: 1, Name: "json"}, &out))
+	require.Equal(t, testUser{ID: 1, Name: "json"}, out)
+
+	out = testUser{}
+	require.NoError(t, cli.XML(ctx, http.MethodPost, srv.URL+"/user", testUser{ID: 2, Name: "xml"}, &out))
+	require.Equal(t, 2, out.ID)
+	require.Equal(t, "xml", out.Name)
+
+	out = testUser{}
+	require.NoError(t, cli.XML(ctx, http.MethodPost, srv.URL+"/plain", testUser{ID: 3, Name: "plain"}, &out))
+	require.Equal(t, 3, out.ID)
+	require.Equal(t, "plain", out.Name)
+
+	err := cli.JSON(ctx, http.MethodGet, srv.URL+"/problem", nil, &out)
+	var httpErr *web.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+	require.Equal(t, &web.Problem{Type: "about:blank", Title: "Forbidden", Status: 403, Detail: "no access"}, httpErr.Problem)
+	require.Equal(t, "http error: 403 Forbidden: Forbidden: no access", err.Error())
+
+	err = cli.JSON(ctx, http.MethodGet, srv.URL+"/error", nil, &out)
+	require.True(t, errors.As(err, &httpErr))
+	require.Nil(t, httpErr.Problem)
+	var custom struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	require.NoError(t, httpErr.Decode(&custom))
+	require.Equal(t, 42, custom.Code)
+	require.Equal(t, "bad", custom.Message)
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/signature"
+)
+
+type (
+	streamKey   struct{}
+	noVerifyKey struct{}
+)
+
+//withStream marking request as streaming, response body is not buffered for validation of signature
+func withStream(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamKey{}, true)
+}
+
+func isStream(ctx context.Context) bool {
+	v, ok := ctx.Value(streamKey{}).(bool)
+	return ok && v
+}
+
+//WithoutVerify disabling validation of response signature for request,
+//it is required for streaming requests (Request.Stream, Upload, Download) if client verifies responses
+func WithoutVerify(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noVerifyKey{}, true)
+}
+
+func isNoVerify(ctx context.Context) bool {
+	v, ok := ctx.Value(noVerifyKey{}).(bool)
+	return ok && v
+}
+
+//WithVerify enable validation of response signature from `Signature` header,
+//responses without valid signature are returned as error (see SignatureVerifyInterceptor)
+func (v *Client) WithVerify(store *signature.Storage) {
+	v.verifier = store
+}
+
+//SignatureVerifyInterceptor validation of response signature from `Signature` header,
+//status, method and request target are validated with body (see signature.ResponseData),
+//partial responses (206) to requests with `Range` are not validated,
+//streaming requests (Request.Stream, Upload, Download) are not sent unless validation is disabled by WithoutVerify
+func SignatureVerifyInterceptor(store *signature.Storage) Interceptor {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if isNoVerify(r.Context()) {
+				return next(r)
+			}
+			if isStream(r.Context()) {
+				//body of streaming response is not buffered, signature can not be validated
+				return nil, &requestError{err: errors.WrapMessage(errs.ErrUnverifiedStream, "request to %s", r.URL.String())}
+			}
+			resp, err := next(r)
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode == http.StatusPartialContent && len(r.Header.Get("Range")) > 0 {
+				return resp, nil
+			}
+			b, err := internal.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			data, err := signature.Decode(resp.Header)
+			if err != nil {
+				return nil, errors.WrapMessage(errs.ErrInvalidSignature, "response of %s", r.URL.String())
+			}
+			req := r
+			if resp.Request != nil {
+				req = resp.Request
+			}
+			signed := signature.ResponseData(resp.StatusCode, req.Method, req.URL.RequestURI(), b)
+			if _, err = store.Verify(data.ID, data.Alg, signed, data.Hash); err != nil {
+				return nil, errors.WrapMessage(err, "response of %s", r.URL.String())
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(b))
+			return resp, nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/clients/web"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/routes"
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_ClientVerify(t *testing.T) {
+	serverKey := signature.NewSHA256("server", "secret-1")
+	clientKey := signature.NewSHA256("client", "secret-2")
+
+	serverStore := signature.NewStorage()
+	serverStore.Add(clientKey)
+	route := routes.NewRouter()
+	route.Global(routes.SignatureMiddleware(serverStore), routes.SignResponseMiddleware(serverKey))
+	route.Route("/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong")) //nolint: errcheck
+	}, http.MethodPost)
+	srv := httptest.NewServer(route)
+	defer srv.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("pong"))
+	}))
+	defer plain.Close()
+	//signature of body only, as it can be replayed from response to other request
+	replay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature.Encode(w.Header(), serverKey, []byte("pong"))
+		w.Write([]byte("pong")) //nolint: errcheck
+	}))
+	defer replay.Close()
+	partial := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(`{"admin":true}`)) //nolint: errcheck
+	}))
+	defer partial.Close()
+
+	clientStore := signature.NewStorage()
+	clientStore.Add(serverKey)
+	cli := web.New()
+	cli.WithAuth(clientKey)
+	cli.WithVerify(clientStore)
+
+	code, b, err := cli.Call(http.MethodPost, srv.URL+"/echo", []byte("ping"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "pong", string(b))
+
+	_, _, err = cli.Call(http.MethodPost, plain.URL, []byte("ping"))
+	require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+
+	_, _, err = cli.Call(http.MethodPost, replay.URL, []byte("ping"))
+	require.Error(t, err)
+
+	//streaming responses are not buffered for validation, it must be disabled explicitly
+	var buf bytes.Buffer
+	_, err = cli.Download(context.Background(), plain.URL, &buf, 0, nil)
+	require.True(t, errors.Is(err, errs.ErrUnverifiedStream))
+	_, err = cli.Upload(context.Background(), http.MethodPost, plain.URL, web.NewMultipart().Field("a", "b"), nil)
+	require.True(t, errors.Is(err, errs.ErrUnverifiedStream))
+	n, err := cli.Download(web.WithoutVerify(context.Background()), plain.URL, &buf, 0, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), n)
+	require.Equal(t, "pong", buf.String())
+
+	resp, err := cli.Do(context.Background(), web.Request{URL: plain.URL, Header: http.Header{"Range": {"bytes=2-"}}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPartialContent, resp.Status)
+	resp.Close() //nolint: errcheck
+
+	//unsigned partial response to request without range
+	_, _, err = cli.Call(http.MethodGet, partial.URL, nil)
+	require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+
+	otherStore := signature.NewStorage()
+	otherStore.Add(signature.NewSHA256("server", "other"))
+	cli.WithVerify(otherStore)
+	_, err = cli.Do(context.Background(), web.Request{Method: http.MethodPost, URL: srv.URL + "/echo"})
+	require.Error(t, err)
+}
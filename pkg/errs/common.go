@@ -3,14 +3,32 @@ package errs
 import "github.com/deweppro/go-errors"
 
 var (
-	ErrResolveTCPAddress      = errors.New("resolve tcp address")
-	ErrInvalidSignature       = errors.New("invalid signature header")
-	ErrEmptyPool              = errors.New("empty pool")
-	ErrInvalidPoolAddress     = errors.New("invalid address")
-	ErrServAlreadyRunning     = errors.New("server already running")
-	ErrServAlreadyStopped     = errors.New("server already stopped")
-	ErrInvalidPoolType        = errors.New("invalid data type from pool")
-	ErrEpollEmptyEvents       = errors.New("epoll empty event")
-	ErrFailContextKey         = errors.New("context key is not found")
-	ErrUnsupportedNetworkType = errors.New("unsupported network type")
+	ErrResolveTCPAddress       = errors.New("resolve tcp address")
+	ErrInvalidSignature        = errors.New("invalid signature header")
+	ErrEmptyPool               = errors.New("empty pool")
+	ErrInvalidPoolAddress      = errors.New("invalid address")
+	ErrServAlreadyRunning      = errors.New("server already running")
+	ErrServAlreadyStopped      = errors.New("server already stopped")
+	ErrInvalidPoolType         = errors.New("invalid data type from pool")
+	ErrEpollEmptyEvents        = errors.New("epoll empty event")
+	ErrFailContextKey          = errors.New("context key is not found")
+	ErrUnsupportedNetworkType  = errors.New("unsupported network type")
+	ErrSignatureKeyNotFound    = errors.New("signature key not found")
+	ErrSignatureMismatch       = errors.New("signature mismatch")
+	ErrSignatureExpired        = errors.New("signature expired")
+	ErrSignatureReplayed       = errors.New("signature replayed")
+	ErrInvalidContentDigest    = errors.New("invalid content digest")
+	ErrInvalidKey              = errors.New("invalid key")
+	ErrUnsupportedKey          = errors.New("unsupported key type")
+	ErrInvalidToken            = errors.New("invalid token")
+	ErrInvalidHtpasswd         = errors.New("invalid htpasswd")
+	ErrInvalidBalancerStrategy = errors.New("invalid balancer strategy")
+	ErrInvalidDiscoveryData    = errors.New("invalid discovery data")
+	ErrCircuitOpen             = errors.New("circuit breaker is open")
+	ErrInvalidTLSConfig        = errors.New("invalid tls config")
+	ErrHijackNotSupported      = errors.New("hijacking is not supported")
+	ErrResourceChanged         = errors.New("resource changed")
+	ErrInvalidContentRange     = errors.New("invalid content range")
+	ErrBodyTooLarge            = errors.New("body too large")
+	ErrUnverifiedStream        = errors.New("response of streaming request can not be verified")
 )
@@ -0,0 +1,264 @@
+package pool
+
+import (
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+var (
+	_ PoolGetter = (*Balancer)(nil)
+	_ Tracker    = (*Balancer)(nil)
+)
+
+const (
+	StrategyRoundRobin         = "round-robin"
+	StrategyWeightedRoundRobin = "weighted-round-robin"
+	StrategyLeastOutstanding   = "least-outstanding"
+	StrategyConsistentHash     = "consistent-hash"
+
+	defaultMaxFails = 5
+	defaultCooldown = 30 * time.Second
+	defaultReplicas = 100
+)
+
+type (
+	//Tracker pool with tracking of request results
+	Tracker interface {
+		PoolGetter
+		Next(key string, exclude ...*Endpoint) (*Endpoint, error)
+		Done(ep *Endpoint, ok bool)
+		Release(ep *Endpoint)
+	}
+
+	//Target address of pool item with weight
+	Target struct {
+		Addr   string `yaml:"addr" json:"addr"`
+		Weight int    `yaml:"weight" json:"weight"`
+	}
+
+	//BalancerConfig model
+	BalancerConfig struct {
+		//Strategy: round-robin (default), weighted-round-robin, least-outstanding, consistent-hash
+		Strategy string `yaml:"strategy" json:"strategy"`
+		//MaxFails consecutive failures before ejection of endpoint
+		MaxFails int `yaml:"max_fails" json:"max_fails"`
+		//Cooldown time of endpoint ejection
+		Cooldown time.Duration `yaml:"cooldown" json:"cooldown"`
+		//Replicas count of virtual nodes per endpoint for consistent hash
+		Replicas int `yaml:"replicas" json:"replicas"`
+	}
+)
+
+//Targets getting targets with default weight
+func (v List) Targets() []Target {
+	result := make([]Target, 0, len(v))
+	for _, addr := range v {
+		result = append(result, Target{Addr: addr, Weight: 1})
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//Endpoint pre-parsed pool item with passive health state
+type Endpoint struct {
+	url *url.URL
+
+	weight      int64
+	outstanding int64
+	fails       int64
+	ejected     int64
+	unhealthy   int32
+
+	current int
+}
+
+//URL getting copy of endpoint url
+func (v *Endpoint) URL() *url.URL {
+	u := *v.url
+	return &u
+}
+
+//Weight of endpoint
+func (v *Endpoint) Weight() int {
+	return int(atomic.LoadInt64(&v.weight))
+}
+
+//Outstanding count of requests in progress
+func (v *Endpoint) Outstanding() int64 {
+	return atomic.LoadInt64(&v.outstanding)
+}
+
+//Ejected endpoint is excluded after consecutive failures
+func (v *Endpoint) Ejected() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&v.ejected)
+}
+
+//Healthy endpoint passed last active health check
+func (v *Endpoint) Healthy() bool {
+	return atomic.LoadInt32(&v.unhealthy) == 0
+}
+
+func (v *Endpoint) setHealthy(ok bool) {
+	var val int32
+	if !ok {
+		val = 1
+	}
+	atomic.StoreInt32(&v.unhealthy, val)
+}
+
+func parseEndpoint(t Target) (*Endpoint, error) {
+	u, err := url.Parse(t.Addr)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return nil, errors.WrapMessage(errs.ErrInvalidPoolAddress, "`%s`", t.Addr)
+	}
+	if t.Weight <= 0 {
+		t.Weight = 1
+	}
+	return &Endpoint{url: u, weight: int64(t.Weight)}, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//Balancer pool of endpoints with balancing strategy and passive health tracking
+type Balancer struct {
+	conf      BalancerConfig
+	endpoints []*Endpoint
+	strategy  strategy
+	lock      sync.Mutex
+}
+
+//NewBalancer init balancer
+func NewBalancer(conf BalancerConfig, targets ...Target) (*Balancer, error) {
+	if conf.MaxFails <= 0 {
+		conf.MaxFails = defaultMaxFails
+	}
+	if conf.Cooldown <= 0 {
+		conf.Cooldown = defaultCooldown
+	}
+	if conf.Replicas <= 0 {
+		conf.Replicas = defaultReplicas
+	}
+	list := make([]*Endpoint, 0, len(targets))
+	for _, t := range targets {
+		ep, err := parseEndpoint(t)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, ep)
+	}
+	strategy, err := newStrategy(conf)
+	if err != nil {
+		return nil, err
+	}
+	strategy.reset(list)
+	return &Balancer{
+		conf:      conf,
+		endpoints: list,
+		strategy:  strategy,
+	}, nil
+}
+
+//Update replace endpoints, state of existing endpoints is kept
+func (v *Balancer) Update(targets ...Target) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	exists := make(map[string]*Endpoint, len(v.endpoints))
+	for _, ep := range v.endpoints {
+		exists[ep.url.String()] = ep
+	}
+	list := make([]*Endpoint, 0, len(targets))
+	for _, t := range targets {
+		ep, err := parseEndpoint(t)
+		if err != nil {
+			return err
+		}
+		if old, ok := exists[ep.url.String()]; ok {
+			//weight is read without lock by Endpoint.Weight
+			atomic.StoreInt64(&old.weight, ep.weight)
+			ep = old
+		}
+		list = append(list, ep)
+	}
+	v.endpoints = list
+	v.strategy.reset(list)
+	return nil
+}
+
+//Endpoints getting all endpoints
+func (v *Balancer) Endpoints() []*Endpoint {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return append(make([]*Endpoint, 0, len(v.endpoints)), v.endpoints...)
+}
+
+//Pool getting url of next endpoint
+func (v *Balancer) Pool() (*url.URL, error) {
+	ep, err := v.pick("")
+	if err != nil {
+		return nil, err
+	}
+	return ep.URL(), nil
+}
+
+//Next getting next endpoint for request, key is used by consistent hash strategy,
+//excluded endpoints (example: already failed on retry) are used only if there are no others,
+//Done must be called after request
+func (v *Balancer) Next(key string, exclude ...*Endpoint) (*Endpoint, error) {
+	ep, err := v.pick(key, exclude...)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&ep.outstanding, 1)
+	return ep, nil
+}
+
+//Done complete request to endpoint, endpoint is ejected after consecutive failures
+func (v *Balancer) Done(ep *Endpoint, ok bool) {
+	atomic.AddInt64(&ep.outstanding, -1)
+	if ok {
+		atomic.StoreInt64(&ep.fails, 0)
+		return
+	}
+	if atomic.AddInt64(&ep.fails, 1) >= int64(v.conf.MaxFails) {
+		atomic.StoreInt64(&ep.fails, 0)
+		atomic.StoreInt64(&ep.ejected, time.Now().Add(v.conf.Cooldown).UnixNano())
+	}
+}
+
+//Release complete request to endpoint without result (example: request is not sent or canceled)
+func (v *Balancer) Release(ep *Endpoint) {
+	atomic.AddInt64(&ep.outstanding, -1)
+}
+
+func (v *Balancer) pick(key string, exclude ...*Endpoint) (*Endpoint, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if len(v.endpoints) == 0 {
+		return nil, errs.ErrEmptyPool
+	}
+	if len(exclude) > 0 {
+		ep := v.strategy.next(key, func(ep *Endpoint) bool {
+			return available(ep) && !hasEndpoint(exclude, ep)
+		})
+		if ep != nil {
+			return ep, nil
+		}
+	}
+	if ep := v.strategy.next(key, available); ep != nil {
+		return ep, nil
+	}
+	//all endpoints are unhealthy or ejected, use any of them
+	return v.strategy.next(key, func(*Endpoint) bool { return true }), nil
+}
+
+func available(ep *Endpoint) bool {
+	return ep.Healthy() && !ep.Ejected()
+}
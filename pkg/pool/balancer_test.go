@@ -0,0 +1,115 @@
+package pool_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func hosts(t *testing.T, b *pool.Balancer, key string, n int) map[string]int {
+	result := make(map[string]int)
+	for i := 0; i < n; i++ {
+		ep, err := b.Next(key)
+		require.NoError(t, err)
+		result[ep.URL().Host]++
+		b.Done(ep, true)
+	}
+	return result
+}
+
+func TestUnit_Balancer(t *testing.T) {
+	list := pool.List{"http://a", "http://b", "http://c"}
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, list.Targets()...)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 2, "b": 2, "c": 2}, hosts(t, b, "", 6))
+
+	u, err := b.Pool()
+	require.NoError(t, err)
+	u.Host = "changed"
+	require.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, hosts(t, b, "", 3))
+
+	b, err = pool.NewBalancer(pool.BalancerConfig{Strategy: pool.StrategyWeightedRoundRobin},
+		pool.Target{Addr: "http://a", Weight: 5}, pool.Target{Addr: "http://b", Weight: 1}, pool.Target{Addr: "http://c", Weight: 1})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 5, "b": 1, "c": 1}, hosts(t, b, "", 7))
+
+	b, err = pool.NewBalancer(pool.BalancerConfig{Strategy: pool.StrategyLeastOutstanding}, list.Targets()...)
+	require.NoError(t, err)
+	ep1, err := b.Next("")
+	require.NoError(t, err)
+	ep2, err := b.Next("")
+	require.NoError(t, err)
+	ep3, err := b.Next("")
+	require.NoError(t, err)
+	require.NotEqual(t, ep1.URL().Host, ep2.URL().Host)
+	require.NotEqual(t, ep2.URL().Host, ep3.URL().Host)
+	b.Done(ep2, true)
+	ep4, err := b.Next("")
+	require.NoError(t, err)
+	require.Equal(t, ep2.URL().Host, ep4.URL().Host)
+
+	b, err = pool.NewBalancer(pool.BalancerConfig{Strategy: pool.StrategyConsistentHash}, list.Targets()...)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.Len(t, hosts(t, b, "user-"+strconv.Itoa(i), 5), 1)
+	}
+
+	_, err = pool.NewBalancer(pool.BalancerConfig{Strategy: "unknown"}, list.Targets()...)
+	require.Error(t, err)
+	_, err = pool.NewBalancer(pool.BalancerConfig{}, pool.Target{Addr: "127.0.0.1"})
+	require.Error(t, err)
+
+	b, err = pool.NewBalancer(pool.BalancerConfig{})
+	require.NoError(t, err)
+	_, err = b.Next("")
+	require.EqualError(t, err, errs.ErrEmptyPool.Error())
+}
+
+func TestUnit_BalancerEjection(t *testing.T) {
+	b, err := pool.NewBalancer(pool.BalancerConfig{MaxFails: 2, Cooldown: 100 * time.Millisecond},
+		pool.List{"http://a", "http://b"}.Targets()...)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		ep, err := b.Next("")
+		require.NoError(t, err)
+		b.Done(ep, ep.URL().Host != "a")
+	}
+	require.Equal(t, map[string]int{"b": 4}, hosts(t, b, "", 4))
+
+	for _, ep := range b.Endpoints() {
+		require.Equal(t, ep.URL().Host == "a", ep.Ejected())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, map[string]int{"a": 2, "b": 2}, hosts(t, b, "", 4))
+}
+
+func TestUnit_BalancerUpdateWeight(t *testing.T) {
+	b, err := pool.NewBalancer(pool.BalancerConfig{Strategy: pool.StrategyWeightedRoundRobin},
+		pool.Target{Addr: "http://a", Weight: 1})
+	require.NoError(t, err)
+	ep := b.Endpoints()[0]
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 100; i++ {
+			if err := b.Update(pool.Target{Addr: "http://a", Weight: i}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		require.Greater(t, ep.Weight(), 0)
+	}
+	<-done
+	require.Equal(t, 100, ep.Weight())
+	require.Equal(t, ep, b.Endpoints()[0])
+}
@@ -0,0 +1,153 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-logger"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultDiscoveryInterval = 30 * time.Second
+
+//Discovery source of pool services
+type Discovery interface {
+	//Discover getting current services (name -> addresses) and time until next refresh,
+	//interval of watcher is used if ttl is zero
+	Discover(ctx context.Context) (map[string][]string, time.Duration, error)
+}
+
+//Watcher refreshing pool from discovery source
+type Watcher struct {
+	source   Discovery
+	pool     *Registry
+	interval time.Duration
+	log      logger.Logger
+	onUpdate func(map[string][]string)
+	last     map[string][]string
+
+	status int64
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+}
+
+//NewWatcher init watcher, services of registry are replaced with discovered services on change
+func NewWatcher(source Discovery, interval time.Duration, pool *Registry, log logger.Logger) *Watcher {
+	if interval <= 0 {
+		interval = defaultDiscoveryInterval
+	}
+	return &Watcher{
+		source:   source,
+		pool:     pool,
+		interval: interval,
+		log:      log,
+	}
+}
+
+//OnUpdate setting callback for changes of services (example: updating of balancers)
+func (v *Watcher) OnUpdate(call func(map[string][]string)) {
+	v.lock.Lock()
+	v.onUpdate = call
+	v.lock.Unlock()
+}
+
+//Load discover services and update pool
+func (v *Watcher) Load(ctx context.Context) (time.Duration, error) {
+	items, ttl, err := v.source.Discover(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if ttl <= 0 {
+		ttl = v.interval
+	}
+
+	v.lock.Lock()
+	if reflect.DeepEqual(v.last, items) {
+		v.lock.Unlock()
+		return ttl, nil
+	}
+	v.last = items
+	call := v.onUpdate
+	v.pool.Set(items)
+	v.lock.Unlock()
+
+	if call != nil {
+		call(copyItems(items))
+	}
+	return ttl, nil
+}
+
+//Up load services and run refreshing in background
+func (v *Watcher) Up() error {
+	if !atomic.CompareAndSwapInt64(&v.status, 0, 1) {
+		return errors.WrapMessage(errs.ErrServAlreadyRunning, "discovery watcher")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ttl, err := v.Load(ctx)
+	if err != nil {
+		cancel()
+		atomic.StoreInt64(&v.status, 0)
+		return err
+	}
+	v.cancel = cancel
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ttl):
+			}
+
+			next, err := v.Load(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				v.log.WithFields(logger.Fields{"err": err.Error()}).Errorf("discovery of pool services")
+				next = v.interval
+			}
+			ttl = next
+		}
+	}()
+	return nil
+}
+
+//Down stop refreshing, discovery in progress is canceled
+func (v *Watcher) Down() error {
+	if !atomic.CompareAndSwapInt64(&v.status, 1, 0) {
+		return errors.WrapMessage(errs.ErrServAlreadyStopped, "discovery watcher")
+	}
+	v.cancel()
+	v.wg.Wait()
+	return nil
+}
+
+//DecodeServices decoding services from yaml or json (by file extension) in pool config format
+func DecodeServices(filename string, data []byte) (map[string][]string, error) {
+	conf := Pool{}
+	var err error
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(data, &conf)
+	} else {
+		err = yaml.Unmarshal(data, &conf)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidDiscoveryData)
+	}
+	if conf.Items == nil {
+		conf.Items = make(map[string][]string)
+	}
+	return conf.Items, nil
+}
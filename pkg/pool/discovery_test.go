@@ -0,0 +1,121 @@
+package pool_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/deweppro/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_FileDiscovery(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "services.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte("services:\n  api: [\"http://a\"]\n"), 0600))
+
+	conf := pool.NewRegistry(nil)
+	w := pool.NewWatcher(pool.NewFileDiscovery(filename), 20*time.Millisecond, conf, logger.Default())
+	updates := make([]map[string][]string, 0)
+	mux := sync.Mutex{}
+	w.OnUpdate(func(m map[string][]string) {
+		mux.Lock()
+		updates = append(updates, m)
+		mux.Unlock()
+	})
+
+	require.NoError(t, w.Up())
+	require.Equal(t, pool.List{"http://a"}, conf.Get("api"))
+
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filename, []byte("services:\n  api: [\"http://a\", \"http://b\"]\n  web: [\"http://c\"]\n"), 0600))
+	require.NoError(t, os.Chtimes(filename, time.Now(), time.Now().Add(time.Second)))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, w.Down())
+
+	require.Equal(t, pool.List{"http://a", "http://b"}, conf.Get("api"))
+	require.Equal(t, pool.List{"http://c"}, conf.Get("web"))
+	mux.Lock()
+	require.Len(t, updates, 2)
+	mux.Unlock()
+}
+
+func TestUnit_HTTPDiscovery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=15")
+		w.Write([]byte(`{"services":{"api":["http://a","http://b"]}}`)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	items, ttl, err := pool.NewHTTPDiscovery(srv.URL, nil).Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 15*time.Second, ttl)
+	require.Equal(t, map[string][]string{"api": {"http://a", "http://b"}}, items)
+}
+
+func TestUnit_DNSDiscovery(t *testing.T) {
+	d := pool.NewDNSDiscovery(pool.DNSConfig{
+		Services: map[string]pool.DNSRecord{"local": {Type: pool.DNSRecordA, Name: "localhost", Port: 8080}},
+		TTL:      time.Minute,
+	})
+	items, ttl, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, ttl)
+	require.NotEmpty(t, items["local"])
+	require.Contains(t, []string{"http://127.0.0.1:8080", "http://[::1]:8080"}, items["local"][0])
+}
+
+func TestUnit_PoolUpdate(t *testing.T) {
+	conf := pool.NewRegistry(&pool.Pool{Items: map[string][]string{"web": {"http://c"}}})
+	require.Equal(t, pool.List{"http://c"}, conf.Get("web"))
+	conf.Update("web", nil)
+	conf.Update("api", []string{"http://a"})
+	list := conf.Get("api")
+	conf.Set(map[string][]string{"api": {"http://b"}})
+	require.Equal(t, pool.List{"http://a"}, list)
+	require.Equal(t, pool.List{"http://b"}, conf.Get("api"))
+	conf.Update("api", nil)
+	require.Empty(t, conf.Names())
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{"http://a", "http://b"}.Targets()...)
+	require.NoError(t, err)
+	a := b.Endpoints()[0]
+	require.NoError(t, b.Update(pool.List{"http://a", "http://c"}.Targets()...))
+	require.Same(t, a, b.Endpoints()[0])
+	require.Equal(t, map[string]int{"a": 1, "c": 1}, hosts(t, b, "", 2))
+}
+
+type blockDiscovery struct {
+	calls int32
+}
+
+func (v *blockDiscovery) Discover(ctx context.Context) (map[string][]string, time.Duration, error) {
+	if atomic.AddInt32(&v.calls, 1) == 1 {
+		return map[string][]string{}, 10 * time.Millisecond, nil
+	}
+	<-ctx.Done()
+	return nil, 0, ctx.Err()
+}
+
+func TestUnit_WatcherDownCancel(t *testing.T) {
+	source := &blockDiscovery{}
+	w := pool.NewWatcher(source, time.Minute, pool.NewRegistry(nil), logger.Default())
+	require.NoError(t, w.Up())
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&source.calls) > 1 }, time.Second, 5*time.Millisecond)
+
+	done := make(chan error)
+	go func() { done <- w.Down() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("watcher is not stopped")
+	}
+}
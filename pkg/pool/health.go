@@ -0,0 +1,230 @@
+package pool
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 2 * time.Second
+)
+
+type (
+	//HealthConfig model
+	HealthConfig struct {
+		//Path of health check endpoint (example: /health)
+		Path string `yaml:"path" json:"path"`
+		//Status expected response status, 200 if empty
+		Status   int           `yaml:"status" json:"status"`
+		Timeout  time.Duration `yaml:"timeout" json:"timeout"`
+		Interval time.Duration `yaml:"interval" json:"interval"`
+		//Jitter max random delay added to interval
+		Jitter time.Duration `yaml:"jitter" json:"jitter"`
+	}
+
+	//HealthEvent event of endpoint state change
+	HealthEvent struct {
+		URL     string
+		Healthy bool
+		Err     error
+		Time    time.Time
+	}
+
+	//HealthState state of endpoint
+	HealthState struct {
+		URL       string    `json:"url"`
+		Healthy   bool      `json:"healthy"`
+		Ejected   bool      `json:"ejected"`
+		LastCheck time.Time `json:"last_check"`
+		LastError string    `json:"last_error,omitempty"`
+	}
+)
+
+//HealthChecker active health checker of balancer endpoints,
+//unhealthy endpoints are skipped by balancer
+type HealthChecker struct {
+	conf    HealthConfig
+	pool    *Balancer
+	cli     *http.Client
+	onEvent func(HealthEvent)
+
+	states map[*Endpoint]*HealthState
+	lock   sync.RWMutex
+
+	status int64
+	close  chan struct{}
+	wg     sync.WaitGroup
+}
+
+//NewHealthChecker init health checker
+func NewHealthChecker(conf HealthConfig, b *Balancer) *HealthChecker {
+	if conf.Status == 0 {
+		conf.Status = http.StatusOK
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultHealthTimeout
+	}
+	if conf.Interval <= 0 {
+		conf.Interval = defaultHealthInterval
+	}
+	return &HealthChecker{
+		conf:   conf,
+		pool:   b,
+		cli:    &http.Client{Timeout: conf.Timeout},
+		states: make(map[*Endpoint]*HealthState),
+	}
+}
+
+//OnEvent setting callback for endpoint state changes
+func (v *HealthChecker) OnEvent(call func(HealthEvent)) {
+	v.lock.Lock()
+	v.onEvent = call
+	v.lock.Unlock()
+}
+
+//Up run checks in background
+func (v *HealthChecker) Up() error {
+	if !atomic.CompareAndSwapInt64(&v.status, 0, 1) {
+		return errors.WrapMessage(errs.ErrServAlreadyRunning, "health checker")
+	}
+	v.close = make(chan struct{})
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-v.close
+			cancel()
+		}()
+
+		for {
+			v.Check(ctx)
+
+			delay := v.conf.Interval
+			if v.conf.Jitter > 0 {
+				delay += time.Duration(rand.Int63n(int64(v.conf.Jitter)))
+			}
+			select {
+			case <-v.close:
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+	return nil
+}
+
+//Down stop checks
+func (v *HealthChecker) Down() error {
+	if !atomic.CompareAndSwapInt64(&v.status, 1, 0) {
+		return errors.WrapMessage(errs.ErrServAlreadyStopped, "health checker")
+	}
+	close(v.close)
+	v.wg.Wait()
+	return nil
+}
+
+//Check probe all endpoints once
+func (v *HealthChecker) Check(ctx context.Context) {
+	list := v.pool.Endpoints()
+	wg := sync.WaitGroup{}
+	for _, ep := range list {
+		wg.Add(1)
+		go func(ep *Endpoint) {
+			defer wg.Done()
+			v.update(ctx, ep, v.probe(ctx, ep))
+		}(ep)
+	}
+	wg.Wait()
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	for ep := range v.states {
+		if !hasEndpoint(list, ep) {
+			delete(v.states, ep)
+		}
+	}
+}
+
+//Snapshot getting state of all endpoints
+func (v *HealthChecker) Snapshot() []HealthState {
+	list := v.pool.Endpoints()
+	result := make([]HealthState, 0, len(list))
+
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	for _, ep := range list {
+		state := HealthState{URL: ep.url.String(), Healthy: ep.Healthy()}
+		if s, ok := v.states[ep]; ok {
+			state = *s
+		}
+		state.Ejected = ep.Ejected()
+		result = append(result, state)
+	}
+	return result
+}
+
+func (v *HealthChecker) probe(ctx context.Context, ep *Endpoint) error {
+	u := ep.URL()
+	u.Path = v.conf.Path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close() //nolint: errcheck
+	if resp.StatusCode != v.conf.Status {
+		return errors.New("unexpected status: " + resp.Status)
+	}
+	return nil
+}
+
+func (v *HealthChecker) update(ctx context.Context, ep *Endpoint, err error) {
+	//probe is interrupted by stopping of checker, it is not a result of endpoint
+	if ctx.Err() != nil {
+		return
+	}
+	now := time.Now()
+	healthy := err == nil
+
+	v.lock.Lock()
+	state, ok := v.states[ep]
+	if !ok {
+		state = &HealthState{URL: ep.url.String(), Healthy: true}
+		v.states[ep] = state
+	}
+	changed := state.Healthy != healthy
+	state.Healthy, state.LastCheck, state.LastError = healthy, now, ""
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	call := v.onEvent
+	v.lock.Unlock()
+
+	ep.setHealthy(healthy)
+	if changed && call != nil {
+		call(HealthEvent{URL: state.URL, Healthy: healthy, Err: err, Time: now})
+	}
+}
+
+func hasEndpoint(list []*Endpoint, ep *Endpoint) bool {
+	for _, item := range list {
+		if item == ep {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package pool_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/pool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_HealthChecker(t *testing.T) {
+	var fail int32
+	newServer := func(check bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/health" || (check && atomic.LoadInt32(&fail) == 1) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	}
+	srv1, srv2 := newServer(true), newServer(false)
+	defer srv1.Close()
+	defer srv2.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{srv1.URL, srv2.URL}.Targets()...)
+	require.NoError(t, err)
+
+	hc := pool.NewHealthChecker(pool.HealthConfig{Path: "/health", Interval: 20 * time.Millisecond, Jitter: 5 * time.Millisecond}, b)
+	events := make([]pool.HealthEvent, 0)
+	mux := sync.Mutex{}
+	hc.OnEvent(func(e pool.HealthEvent) {
+		mux.Lock()
+		events = append(events, e)
+		mux.Unlock()
+	})
+
+	hc.Check(context.Background())
+	for _, s := range hc.Snapshot() {
+		require.True(t, s.Healthy)
+	}
+
+	atomic.StoreInt32(&fail, 1)
+	require.NoError(t, hc.Up())
+	require.Error(t, hc.Up())
+	time.Sleep(100 * time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		u, err := b.Pool()
+		require.NoError(t, err)
+		require.Equal(t, srv2.URL, u.String())
+	}
+	for _, s := range hc.Snapshot() {
+		require.Equal(t, s.URL == srv2.URL, s.Healthy)
+	}
+	mux.Lock()
+	require.Len(t, events, 1)
+	require.Equal(t, srv1.URL, events[0].URL)
+	require.False(t, events[0].Healthy)
+	mux.Unlock()
+
+	atomic.StoreInt32(&fail, 0)
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, hc.Down())
+	require.Error(t, hc.Down())
+
+	for _, s := range hc.Snapshot() {
+		require.True(t, s.Healthy)
+	}
+	mux.Lock()
+	require.Len(t, events, 2)
+	require.True(t, events[1].Healthy)
+	mux.Unlock()
+}
+
+func TestUnit_BalancerUnhealthyFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{srv.URL}.Targets()...)
+	require.NoError(t, err)
+	pool.NewHealthChecker(pool.HealthConfig{}, b).Check(context.Background())
+
+	require.False(t, b.Endpoints()[0].Healthy())
+	u, err := b.Pool()
+	require.NoError(t, err)
+	require.Equal(t, srv.URL, u.String())
+}
+
+func TestUnit_HealthCheckerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	b, err := pool.NewBalancer(pool.BalancerConfig{}, pool.List{srv.URL}.Targets()...)
+	require.NoError(t, err)
+	hc := pool.NewHealthChecker(pool.HealthConfig{}, b)
+	hc.Check(ctx)
+
+	require.True(t, b.Endpoints()[0].Healthy())
+	require.True(t, hc.Snapshot()[0].Healthy)
+}
@@ -3,6 +3,7 @@ package pool
 import (
 	"math/rand"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/deweppro/go-http/pkg/errs"
@@ -14,6 +15,7 @@ func init() {
 
 var (
 	_ ConfigGetter = (*Pool)(nil)
+	_ ConfigGetter = (*Registry)(nil)
 	_ PoolGetter   = (*List)(nil)
 )
 
@@ -27,6 +29,12 @@ type (
 	Pool struct {
 		Items map[string][]string `yaml:"services" json:"services"`
 	}
+
+	//Registry thread-safe services of pool, it is updated by Watcher
+	Registry struct {
+		items map[string][]string
+		lock  sync.RWMutex
+	}
 )
 
 func (c *Pool) Get(name string) List {
@@ -37,6 +45,66 @@ func (c *Pool) Get(name string) List {
 	return v
 }
 
+//NewRegistry init registry with copy of services from config, config can be nil
+func NewRegistry(conf *Pool) *Registry {
+	r := &Registry{}
+	if conf != nil {
+		r.items = copyItems(conf.Items)
+	}
+	return r
+}
+
+//Get getting addresses of service
+func (r *Registry) Get(name string) List {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	v, ok := r.items[name]
+	if !ok {
+		return List{}
+	}
+	return v
+}
+
+//Set replace all services
+func (r *Registry) Set(items map[string][]string) {
+	items = copyItems(items)
+	r.lock.Lock()
+	r.items = items
+	r.lock.Unlock()
+}
+
+//Update replace addresses of service, service is removed if list is empty
+func (r *Registry) Update(name string, list []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	items := copyItems(r.items)
+	if len(list) == 0 {
+		delete(items, name)
+	} else {
+		items[name] = append(make([]string, 0, len(list)), list...)
+	}
+	r.items = items
+}
+
+//Names getting names of all services
+func (r *Registry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	result := make([]string, 0, len(r.items))
+	for name := range r.items {
+		result = append(result, name)
+	}
+	return result
+}
+
+func copyItems(items map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(items))
+	for name, list := range items {
+		result[name] = append(make([]string, 0, len(list)), list...)
+	}
+	return result
+}
+
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type (
@@ -0,0 +1,212 @@
+package pool
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+var (
+	_ Discovery = (*FileDiscovery)(nil)
+	_ Discovery = (*DNSDiscovery)(nil)
+	_ Discovery = (*HTTPDiscovery)(nil)
+)
+
+//FileDiscovery services from yaml or json file, file is read again only if it is changed
+type FileDiscovery struct {
+	filename string
+	mod      time.Time
+	items    map[string][]string
+	lock     sync.Mutex
+}
+
+//NewFileDiscovery init file source
+func NewFileDiscovery(filename string) *FileDiscovery {
+	return &FileDiscovery{filename: filename}
+}
+
+//Discover reading services from file
+func (v *FileDiscovery) Discover(_ context.Context) (map[string][]string, time.Duration, error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	fi, err := os.Stat(v.filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	if v.items != nil && fi.ModTime().Equal(v.mod) {
+		return v.items, 0, nil
+	}
+	b, err := os.ReadFile(v.filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := DecodeServices(v.filename, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	v.items, v.mod = items, fi.ModTime()
+	return items, 0, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	DNSRecordA   = "a"
+	DNSRecordSRV = "srv"
+)
+
+//DNSRecord model of service in DNS
+type DNSRecord struct {
+	//Type of record: a (A/AAAA) or srv
+	Type string `yaml:"type" json:"type"`
+	//Name of record (example: _http._tcp.api.example.com for srv)
+	Name string `yaml:"name" json:"name"`
+	//Scheme of addresses, http if empty
+	Scheme string `yaml:"scheme" json:"scheme"`
+	//Port of addresses for A records
+	Port int `yaml:"port" json:"port"`
+}
+
+//DNSConfig model
+type DNSConfig struct {
+	Services map[string]DNSRecord `yaml:"services" json:"services"`
+	//TTL refresh interval of records, stdlib resolver does not expose TTL of response
+	TTL time.Duration `yaml:"ttl" json:"ttl"`
+}
+
+//DNSDiscovery services from DNS SRV or A records
+type DNSDiscovery struct {
+	conf     DNSConfig
+	resolver *net.Resolver
+}
+
+//NewDNSDiscovery init DNS source with default resolver
+func NewDNSDiscovery(conf DNSConfig) *DNSDiscovery {
+	return NewDNSDiscoveryWithResolver(conf, net.DefaultResolver)
+}
+
+//NewDNSDiscoveryWithResolver init DNS source with custom resolver
+func NewDNSDiscoveryWithResolver(conf DNSConfig, r *net.Resolver) *DNSDiscovery {
+	return &DNSDiscovery{conf: conf, resolver: r}
+}
+
+//Discover resolving all records
+func (v *DNSDiscovery) Discover(ctx context.Context) (map[string][]string, time.Duration, error) {
+	result := make(map[string][]string, len(v.conf.Services))
+	for name, rec := range v.conf.Services {
+		list, err := v.resolve(ctx, rec)
+		if err != nil {
+			return nil, 0, errors.WrapMessage(err, "resolve `%s`", name)
+		}
+		sort.Strings(list)
+		result[name] = list
+	}
+	return result, v.conf.TTL, nil
+}
+
+func (v *DNSDiscovery) resolve(ctx context.Context, rec DNSRecord) ([]string, error) {
+	scheme := rec.Scheme
+	if len(scheme) == 0 {
+		scheme = "http"
+	}
+	switch strings.ToLower(rec.Type) {
+	case DNSRecordSRV:
+		_, srvs, err := v.resolver.LookupSRV(ctx, "", "", rec.Name)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]string, 0, len(srvs))
+		for _, srv := range srvs {
+			host := strings.TrimSuffix(srv.Target, ".")
+			result = append(result, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(srv.Port))))
+		}
+		return result, nil
+	case DNSRecordA, "":
+		ips, err := v.resolver.LookupHost(ctx, rec.Name)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			host := ip
+			if rec.Port > 0 {
+				host = net.JoinHostPort(ip, strconv.Itoa(rec.Port))
+			} else if strings.Contains(ip, ":") {
+				host = "[" + ip + "]"
+			}
+			result = append(result, scheme+"://"+host)
+		}
+		return result, nil
+	default:
+		return nil, errors.WrapMessage(errs.ErrInvalidDiscoveryData, "unsupported record type `%s`", rec.Type)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//HTTPDiscovery services from http endpoint in pool config format (json or yaml),
+//`Cache-Control: max-age` of response is used as time until next refresh
+type HTTPDiscovery struct {
+	url string
+	cli *http.Client
+}
+
+//NewHTTPDiscovery init http source
+func NewHTTPDiscovery(url string, cli *http.Client) *HTTPDiscovery {
+	if cli == nil {
+		cli = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPDiscovery{url: url, cli: cli}
+}
+
+//Discover loading services from url
+func (v *HTTPDiscovery) Discover(ctx context.Context) (map[string][]string, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml")
+	resp, err := v.cli.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	b, err := internal.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, errors.WrapMessage(errs.ErrInvalidDiscoveryData, "unexpected status %d", resp.StatusCode)
+	}
+	filename := ".yaml"
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		filename = ".json"
+	}
+	items, err := DecodeServices(filename, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, maxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+func maxAge(cc string) time.Duration {
+	for _, item := range strings.Split(cc, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) == 2 && strings.EqualFold(kv[0], "max-age") {
+			if n, err := strconv.Atoi(kv[1]); err == nil && n > 0 {
+				return time.Duration(n) * time.Second
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+package pool
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+//strategy of balancing, calls are guarded by balancer lock
+type strategy interface {
+	reset(list []*Endpoint)
+	next(key string, allow func(*Endpoint) bool) *Endpoint
+}
+
+func newStrategy(conf BalancerConfig) (strategy, error) {
+	switch conf.Strategy {
+	case "", StrategyRoundRobin:
+		return &roundRobin{}, nil
+	case StrategyWeightedRoundRobin:
+		return &weightedRoundRobin{}, nil
+	case StrategyLeastOutstanding:
+		return &leastOutstanding{}, nil
+	case StrategyConsistentHash:
+		return &consistentHash{replicas: conf.Replicas}, nil
+	default:
+		return nil, errors.WrapMessage(errs.ErrInvalidBalancerStrategy, "`%s`", conf.Strategy)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type roundRobin struct {
+	list []*Endpoint
+	i    int
+}
+
+func (v *roundRobin) reset(list []*Endpoint) {
+	v.list, v.i = list, 0
+}
+
+func (v *roundRobin) next(_ string, allow func(*Endpoint) bool) *Endpoint {
+	n := len(v.list)
+	for j := 0; j < n; j++ {
+		ep := v.list[(v.i+j)%n]
+		if allow(ep) {
+			v.i = (v.i + j + 1) % n
+			return ep
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//weightedRoundRobin smooth weighted round-robin
+type weightedRoundRobin struct {
+	list []*Endpoint
+}
+
+func (v *weightedRoundRobin) reset(list []*Endpoint) {
+	v.list = list
+	for _, ep := range list {
+		ep.current = 0
+	}
+}
+
+func (v *weightedRoundRobin) next(_ string, allow func(*Endpoint) bool) *Endpoint {
+	var (
+		best  *Endpoint
+		total int
+	)
+	for _, ep := range v.list {
+		if !allow(ep) {
+			continue
+		}
+		w := ep.Weight()
+		ep.current += w
+		total += w
+		if best == nil || ep.current > best.current {
+			best = ep
+		}
+	}
+	if best != nil {
+		best.current -= total
+	}
+	return best
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type leastOutstanding struct {
+	roundRobin
+}
+
+func (v *leastOutstanding) next(_ string, allow func(*Endpoint) bool) *Endpoint {
+	var best *Endpoint
+	n := len(v.list)
+	for j := 0; j < n; j++ {
+		ep := v.list[(v.i+j)%n]
+		if !allow(ep) {
+			continue
+		}
+		if best == nil || ep.Outstanding() < best.Outstanding() {
+			best = ep
+		}
+	}
+	if n > 0 {
+		v.i = (v.i + 1) % n
+	}
+	return best
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type consistentHash struct {
+	roundRobin
+	replicas int
+	ring     []uint32
+	nodes    map[uint32]*Endpoint
+}
+
+func (v *consistentHash) reset(list []*Endpoint) {
+	v.roundRobin.reset(list)
+	v.ring = make([]uint32, 0, len(list)*v.replicas)
+	v.nodes = make(map[uint32]*Endpoint, len(list)*v.replicas)
+	for _, ep := range list {
+		for i := 0; i < v.replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(ep.url.String() + "#" + strconv.Itoa(i)))
+			if _, ok := v.nodes[h]; ok {
+				continue
+			}
+			v.nodes[h] = ep
+			v.ring = append(v.ring, h)
+		}
+	}
+	sort.Slice(v.ring, func(i, j int) bool { return v.ring[i] < v.ring[j] })
+}
+
+func (v *consistentHash) next(key string, allow func(*Endpoint) bool) *Endpoint {
+	if len(key) == 0 {
+		return v.roundRobin.next(key, allow)
+	}
+	n := len(v.ring)
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(n, func(i int) bool { return v.ring[i] >= h })
+	for j := 0; j < n; j++ {
+		if ep := v.nodes[v.ring[(i+j)%n]]; allow(ep) {
+			return ep
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package routes
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type principalKey struct{}
+
+//Principal model of authenticated user or service
+type Principal struct {
+	ID     string
+	Roles  []string
+	Scopes []string
+}
+
+//WithPrincipal setting authenticated principal to context
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+//GetPrincipal getting authenticated principal of current request
+func GetPrincipal(r *http.Request) (*Principal, bool) {
+	v, ok := r.Context().Value(principalKey{}).(*Principal)
+	return v, ok && v != nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type (
+	//Verifier interface of credentials lookup, id is empty for API keys
+	Verifier interface {
+		Verify(id, secret string) (*Principal, bool)
+	}
+	//VerifierFunc adapter of function to Verifier
+	VerifierFunc func(id, secret string) (*Principal, bool)
+)
+
+//Verify credentials
+func (f VerifierFunc) Verify(id, secret string) (*Principal, bool) {
+	return f(id, secret)
+}
+
+//NewStaticVerifier verifier of user passwords from list (user => password)
+func NewStaticVerifier(list map[string]string) Verifier {
+	return VerifierFunc(func(id, secret string) (*Principal, bool) {
+		ex, ok := list[id]
+		if subtle.ConstantTimeCompare([]byte(ex), []byte(secret)) != 1 || !ok {
+			return nil, false
+		}
+		return &Principal{ID: id}, true
+	})
+}
+
+//NewKeysVerifier verifier of API keys from list (key => principal ID)
+func NewKeysVerifier(list map[string]string) Verifier {
+	return VerifierFunc(func(_, secret string) (*Principal, bool) {
+		id, found := "", false
+		for key, name := range list {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(secret)) == 1 {
+				id, found = name, true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		return &Principal{ID: id}, true
+	})
+}
+
+var _ Verifier = (*Htpasswd)(nil)
+
+//Htpasswd verifier of user passwords from htpasswd file with bcrypt hashes
+type Htpasswd struct {
+	list map[string][]byte
+	lock sync.RWMutex
+}
+
+//LoadHtpasswd reading htpasswd file
+func LoadHtpasswd(filename string) (*Htpasswd, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint: errcheck
+	return ParseHtpasswd(f)
+}
+
+//ParseHtpasswd reading htpasswd data, only bcrypt hashes are supported
+func ParseHtpasswd(r io.Reader) (*Htpasswd, error) {
+	v := &Htpasswd{list: make(map[string][]byte)}
+	scan := bufio.NewScanner(r)
+	for n := 1; scan.Scan(); n++ {
+		line := strings.TrimSpace(scan.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, errors.WrapMessage(errs.ErrInvalidHtpasswd, "line %d", n)
+		}
+		if _, err := bcrypt.Cost([]byte(kv[1])); err != nil {
+			return nil, errors.WrapMessage(errs.ErrInvalidHtpasswd, "line %d: %s", n, err.Error())
+		}
+		v.list[kv[0]] = []byte(kv[1])
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+//Set adding or replacing user password
+func (v *Htpasswd) Set(user, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	v.lock.Lock()
+	v.list[user] = hash
+	v.lock.Unlock()
+	return nil
+}
+
+//htpasswdDummyHash bcrypt hash with default cost which is compared for unknown users,
+//so response time does not depend on user existence
+var htpasswdDummyHash = []byte("$2a$10$yyTrjMPKtEBmZMhaie20yugox2ZF81KGjaV61yREKrKPupU0e/QYO")
+
+//Verify user password
+func (v *Htpasswd) Verify(id, secret string) (*Principal, bool) {
+	v.lock.RLock()
+	hash, ok := v.list[id]
+	v.lock.RUnlock()
+
+	if !ok {
+		bcrypt.CompareHashAndPassword(htpasswdDummyHash, []byte(secret)) //nolint: errcheck
+		return nil, false
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(secret)) != nil {
+		return nil, false
+	}
+	return &Principal{ID: id}, true
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//BasicAuthMiddleware authentication with `Authorization: Basic` header
+func BasicAuthMiddleware(realm string, v Verifier) func(c CtrlFunc) CtrlFunc {
+	challenge := fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, realm)
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok {
+				authChallenge(w, challenge)
+				return
+			}
+			p, ok := v.Verify(user, pass)
+			if !ok {
+				authChallenge(w, challenge)
+				return
+			}
+			c(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		}
+	}
+}
+
+const (
+	APIKeyHeader = "header"
+	APIKeyQuery  = "query"
+	APIKeyCookie = "cookie"
+
+	defaultAPIKeyName = "X-API-Key"
+)
+
+//APIKeyConfig model
+type APIKeyConfig struct {
+	//Source one of: header, query, cookie
+	Source string `yaml:"source"`
+	Name   string `yaml:"name"`
+}
+
+//APIKeyMiddleware authentication with API key from header, query or cookie
+func APIKeyMiddleware(conf APIKeyConfig, v Verifier) func(c CtrlFunc) CtrlFunc {
+	if len(conf.Source) == 0 {
+		conf.Source = APIKeyHeader
+	}
+	if len(conf.Name) == 0 {
+		conf.Name = defaultAPIKeyName
+	}
+	challenge := fmt.Sprintf(`APIKey in=%q, name=%q`, conf.Source, conf.Name)
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := ""
+			switch conf.Source {
+			case APIKeyQuery:
+				key = r.URL.Query().Get(conf.Name)
+			case APIKeyCookie:
+				if cookie, err := r.Cookie(conf.Name); err == nil {
+					key = cookie.Value
+				}
+			default:
+				key = r.Header.Get(conf.Name)
+			}
+			if len(key) == 0 {
+				authChallenge(w, challenge)
+				return
+			}
+			p, ok := v.Verify("", key)
+			if !ok {
+				authChallenge(w, challenge)
+				return
+			}
+			c(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		}
+	}
+}
+
+func authChallenge(w http.ResponseWriter, challenge string) {
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.WriteHeader(http.StatusUnauthorized)
+}
@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUnit_BasicAuthMiddleware(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+	require.NoError(t, err)
+	htpasswd, err := ParseHtpasswd(strings.NewReader("# users\nuser:" + string(hash) + "\n"))
+	require.NoError(t, err)
+	_, err = ParseHtpasswd(strings.NewReader("user:{SHA}aaa"))
+	require.Error(t, err)
+
+	tests := []struct {
+		name     string
+		verifier Verifier
+	}{
+		{name: "static", verifier: NewStaticVerifier(map[string]string{"user": "pass"})},
+		{name: "htpasswd", verifier: htpasswd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *Principal
+			midd := BasicAuthMiddleware("admin", tt.verifier)(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = GetPrincipal(r)
+			})
+
+			rec := httptest.NewRecorder()
+			midd(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+			require.Equal(t, `Basic realm="admin", charset="UTF-8"`, rec.Header().Get("WWW-Authenticate"))
+
+			rec = httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth("user", "fail")
+			midd(rec, req)
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+			rec = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth("user", "pass")
+			midd(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, &Principal{ID: "user"}, principal)
+		})
+	}
+}
+
+func TestUnit_APIKeyMiddleware(t *testing.T) {
+	verifier := NewKeysVerifier(map[string]string{"key1": "service1", "key2": "service2"})
+
+	tests := []struct {
+		name  string
+		conf  APIKeyConfig
+		apply func(r *http.Request, key string)
+	}{
+		{
+			name: "header",
+			conf: APIKeyConfig{},
+			apply: func(r *http.Request, key string) {
+				r.Header.Set("X-API-Key", key)
+			},
+		},
+		{
+			name: "query",
+			conf: APIKeyConfig{Source: APIKeyQuery, Name: "token"},
+			apply: func(r *http.Request, key string) {
+				r.URL.RawQuery = "token=" + key
+			},
+		},
+		{
+			name: "cookie",
+			conf: APIKeyConfig{Source: APIKeyCookie, Name: "token"},
+			apply: func(r *http.Request, key string) {
+				r.AddCookie(&http.Cookie{Name: "token", Value: key})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var principal *Principal
+			midd := APIKeyMiddleware(tt.conf, verifier)(func(w http.ResponseWriter, r *http.Request) {
+				principal, _ = GetPrincipal(r)
+			})
+
+			rec := httptest.NewRecorder()
+			midd(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+			require.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+			rec = httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.apply(req, "key3")
+			midd(rec, req)
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+			rec = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			tt.apply(req, "key2")
+			midd(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, &Principal{ID: "service2"}, principal)
+		})
+	}
+}
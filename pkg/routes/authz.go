@@ -0,0 +1,135 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+//Requirement interface of authorization rule
+type Requirement interface {
+	Allow(p *Principal) bool
+	String() string
+}
+
+type (
+	roleRequirement  string
+	scopeRequirement string
+	allOfRequirement []Requirement
+	anyOfRequirement []Requirement
+)
+
+//Role requirement of principal role
+func Role(name string) Requirement {
+	return roleRequirement(name)
+}
+
+func (v roleRequirement) Allow(p *Principal) bool {
+	return containsAny(p.Roles, string(v))
+}
+
+func (v roleRequirement) String() string {
+	return "role:" + string(v)
+}
+
+//Scope requirement of principal scope
+func Scope(name string) Requirement {
+	return scopeRequirement(name)
+}
+
+func (v scopeRequirement) Allow(p *Principal) bool {
+	return containsAny(p.Scopes, string(v))
+}
+
+func (v scopeRequirement) String() string {
+	return "scope:" + string(v)
+}
+
+//AllOf requirement of all rules (AND)
+func AllOf(list ...Requirement) Requirement {
+	return allOfRequirement(list)
+}
+
+func (v allOfRequirement) Allow(p *Principal) bool {
+	for _, r := range v {
+		if !r.Allow(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v allOfRequirement) String() string {
+	return joinRequirements(v, " AND ")
+}
+
+//AnyOf requirement of any rule (OR)
+func AnyOf(list ...Requirement) Requirement {
+	return anyOfRequirement(list)
+}
+
+func (v anyOfRequirement) Allow(p *Principal) bool {
+	for _, r := range v {
+		if r.Allow(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v anyOfRequirement) String() string {
+	return joinRequirements(v, " OR ")
+}
+
+func joinRequirements(list []Requirement, sep string) string {
+	vv := make([]string, 0, len(list))
+	for _, r := range list {
+		vv = append(vv, r.String())
+	}
+	return "(" + strings.Join(vv, sep) + ")"
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//Problem model of problem details (RFC 7807)
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+//AuthorizeMiddleware checking of authenticated principal against requirement,
+//use with Router.Middlewares for subtree or wrap controller for single route
+func AuthorizeMiddleware(req Requirement) func(c CtrlFunc) CtrlFunc {
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			p, ok := GetPrincipal(r)
+			if !ok {
+				writeProblem(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			if !req.Allow(p) {
+				writeProblem(w, http.StatusForbidden, "requires "+req.String())
+				return
+			}
+			c(w, r)
+		}
+	}
+}
+
+func writeProblem(w http.ResponseWriter, code int, detail string) {
+	b, err := json.Marshal(Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(code),
+		Status: code,
+		Detail: detail,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(code)
+	w.Write(b) //nolint: errcheck
+}
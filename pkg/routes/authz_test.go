@@ -0,0 +1,81 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deweppro/go-http/pkg/routes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_AuthorizeMiddleware(t *testing.T) {
+	principal := func(p *routes.Principal) routes.MiddlFunc {
+		return func(c routes.CtrlFunc) routes.CtrlFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if p != nil {
+					r = r.WithContext(routes.WithPrincipal(r.Context(), p))
+				}
+				c(w, r)
+			}
+		}
+	}
+	req := routes.AllOf(
+		routes.Role("admin"),
+		routes.AnyOf(routes.Scope("read"), routes.Scope("write")),
+	)
+	require.Equal(t, "(role:admin AND (scope:read OR scope:write))", req.String())
+
+	tests := []struct {
+		name string
+		p    *routes.Principal
+		code int
+	}{
+		{name: "Case1", p: nil, code: http.StatusUnauthorized},
+		{name: "Case2", p: &routes.Principal{ID: "1"}, code: http.StatusForbidden},
+		{name: "Case3", p: &routes.Principal{ID: "1", Roles: []string{"admin"}}, code: http.StatusForbidden},
+		{name: "Case4", p: &routes.Principal{ID: "1", Scopes: []string{"write"}}, code: http.StatusForbidden},
+		{name: "Case5", p: &routes.Principal{ID: "1", Roles: []string{"admin"}, Scopes: []string{"write"}}, code: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := routes.NewRouter()
+			r.Global(principal(tt.p))
+			r.Route("/admin/users", func(w http.ResponseWriter, r *http.Request) {}, http.MethodGet)
+			r.Route("/public", func(w http.ResponseWriter, r *http.Request) {}, http.MethodGet)
+			r.Route("/users", routes.AuthorizeMiddleware(routes.Scope("read"))(
+				func(w http.ResponseWriter, r *http.Request) {}), http.MethodGet)
+			r.Middlewares("/admin", routes.AuthorizeMiddleware(req))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users", nil))
+			require.Equal(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+				var problem routes.Problem
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+				require.Equal(t, tt.code, problem.Status)
+			}
+
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/public", nil))
+			require.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+
+	r := routes.NewRouter()
+	r.Global(principal(&routes.Principal{ID: "1", Scopes: []string{"read"}}))
+	r.Route("/users", routes.AuthorizeMiddleware(routes.Scope("read"))(
+		func(w http.ResponseWriter, r *http.Request) {}), http.MethodGet)
+	r.Route("/users/edit", routes.AuthorizeMiddleware(routes.Scope("write"))(
+		func(w http.ResponseWriter, r *http.Request) {}), http.MethodGet)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/edit", nil))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
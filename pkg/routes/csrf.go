@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/deweppro/go-http/pkg/signature"
+)
+
+const (
+	defaultCSRFCookie = "csrf_token"
+	defaultCSRFHeader = "X-CSRF-Token"
+	defaultCSRFField  = "csrf_token"
+	csrfTokenSize     = 32
+)
+
+type csrfTokenKey struct{}
+
+//CSRFConfig model
+type CSRFConfig struct {
+	CookieName string `yaml:"cookie_name"`
+	HeaderName string `yaml:"header_name"`
+	FieldName  string `yaml:"field_name"`
+	Domain     string `yaml:"domain"`
+	Path       string `yaml:"path"`
+	MaxAge     int    `yaml:"max_age"`
+	Secure     bool   `yaml:"secure"`
+	//SameSite one of: lax, strict, none
+	SameSite string `yaml:"same_site"`
+	//Exempt list of URL prefixes without check, example: API with signature auth
+	Exempt []string `yaml:"exempt"`
+}
+
+func (v *CSRFConfig) validate() {
+	if len(v.CookieName) == 0 {
+		v.CookieName = defaultCSRFCookie
+	}
+	if len(v.HeaderName) == 0 {
+		v.HeaderName = defaultCSRFHeader
+	}
+	if len(v.FieldName) == 0 {
+		v.FieldName = defaultCSRFField
+	}
+	if len(v.Path) == 0 {
+		v.Path = separate
+	}
+}
+
+func (v *CSRFConfig) sameSite() http.SameSite {
+	switch strings.ToLower(v.SameSite) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+//CSRFMiddleware protection against Cross-Site Request Forgery
+//with double-submit cookie which contains token signed by signer
+func CSRFMiddleware(conf CSRFConfig, s signature.SignGetter) func(c CtrlFunc) CtrlFunc {
+	conf.validate()
+	exempt := make([][]string, 0, len(conf.Exempt))
+	for _, uri := range conf.Exempt {
+		exempt = append(exempt, split(uri))
+	}
+
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if hasPrefix(split(r.URL.Path), exempt) {
+				c(w, r)
+				return
+			}
+
+			token := ""
+			if cookie, err := r.Cookie(conf.CookieName); err == nil && validCSRFToken(s, cookie.Value) {
+				token = cookie.Value
+			}
+
+			if !isSafeMethod(r.Method) {
+				if len(token) == 0 || !equalCSRFToken(token, submittedCSRFToken(r, conf)) {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+			}
+
+			if len(token) == 0 {
+				var err error
+				if token, err = newCSRFToken(s); err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				http.SetCookie(w, &http.Cookie{
+					Name:     conf.CookieName,
+					Value:    token,
+					Path:     conf.Path,
+					Domain:   conf.Domain,
+					MaxAge:   conf.MaxAge,
+					Secure:   conf.Secure,
+					HttpOnly: true,
+					SameSite: conf.sameSite(),
+				})
+			}
+			w.Header().Add("Vary", "Cookie")
+
+			c(w, r.WithContext(context.WithValue(r.Context(), csrfTokenKey{}, token)))
+		}
+	}
+}
+
+//CSRFToken getting CSRF token for current request
+func CSRFToken(r *http.Request) string {
+	if v, ok := r.Context().Value(csrfTokenKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+func newCSRFToken(s signature.SignGetter) (string, error) {
+	b := make([]byte, csrfTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	v := hex.EncodeToString(b)
+	return v + "." + s.CreateString([]byte(v)), nil
+}
+
+func validCSRFToken(s signature.SignGetter, token string) bool {
+	i := strings.IndexByte(token, '.')
+	if i <= 0 || i == len(token)-1 {
+		return false
+	}
+	return s.Validate([]byte(token[:i]), token[i+1:])
+}
+
+func equalCSRFToken(a, b string) bool {
+	return len(b) > 0 && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func submittedCSRFToken(r *http.Request, conf CSRFConfig) string {
+	if v := r.Header.Get(conf.HeaderName); len(v) > 0 {
+		return v
+	}
+	return r.PostFormValue(conf.FieldName)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasPrefix(uris []string, list [][]string) bool {
+	for _, prefix := range list {
+		if len(prefix) > len(uris) {
+			continue
+		}
+		ok := true
+		for i := range prefix {
+			if prefix[i] != uris[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_CSRFMiddleware(t *testing.T) {
+	token := ""
+	midd := CSRFMiddleware(CSRFConfig{Exempt: []string{"/api"}}, signature.NewSHA256("csrf", "secret"))(
+		func(w http.ResponseWriter, r *http.Request) {
+			token = CSRFToken(r)
+		})
+
+	rec := httptest.NewRecorder()
+	midd(rec, httptest.NewRequest(http.MethodGet, "/form", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, token)
+	cookies := rec.Result().Cookies()
+	require.Equal(t, 1, len(cookies))
+	require.Equal(t, token, cookies[0].Value)
+
+	rec = httptest.NewRecorder()
+	midd(rec, httptest.NewRequest(http.MethodPost, "/form", nil))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/form", nil)
+	req.AddCookie(cookies[0])
+	req.Header.Set("X-CSRF-Token", token+"0")
+	midd(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/form", nil)
+	req.AddCookie(cookies[0])
+	req.Header.Set("X-CSRF-Token", token)
+	midd(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 0, len(rec.Result().Cookies()))
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/form", strings.NewReader(url.Values{"csrf_token": {token}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookies[0])
+	midd(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/form", nil)
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "aaa.bbb"})
+	req.Header.Set("X-CSRF-Token", "aaa.bbb")
+	midd(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	midd(rec, httptest.NewRequest(http.MethodPost, "/api/v1/data", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
@@ -14,6 +14,7 @@ type CtrlFunc func(http.ResponseWriter, *http.Request)
 type handler struct {
 	list        map[string]*handler
 	methods     map[string]CtrlFunc
+	versions    map[string]*versioned
 	matcher     *matcher
 	middlewares []MiddlFunc
 	notFound    CtrlFunc
@@ -24,6 +25,7 @@ func newHandler() *handler {
 	return &handler{
 		list:        make(map[string]*handler),
 		methods:     make(map[string]CtrlFunc),
+		versions:    make(map[string]*versioned),
 		matcher:     newMatcher(),
 		middlewares: make([]MiddlFunc, 0),
 	}
@@ -54,6 +56,30 @@ func (v *handler) next(path string, vars internal.VarsData) *handler {
 
 //Route add new route
 func (v *handler) Route(path string, ctrl CtrlFunc, methods []string) {
+	uh := v.build(path)
+	for _, m := range methods {
+		m = strings.ToUpper(m)
+		delete(uh.versions, m)
+		uh.methods[m] = ctrl
+	}
+}
+
+//RouteVersion add new route for API version
+func (v *handler) RouteVersion(ver uint64, path string, ctrl CtrlFunc, methods []string) {
+	uh := v.build(path)
+	for _, m := range methods {
+		m = strings.ToUpper(m)
+		vs, ok := uh.versions[m]
+		if !ok {
+			vs = newVersioned()
+			uh.versions[m] = vs
+		}
+		vs.Add(ver, ctrl)
+		uh.methods[m] = vs.ServeHTTP
+	}
+}
+
+func (v *handler) build(path string) *handler {
 	uh := v
 	uris := split(path)
 	for _, uri := range uris {
@@ -64,9 +90,7 @@ func (v *handler) Route(path string, ctrl CtrlFunc, methods []string) {
 		}
 		uh = uh.append(uri)
 	}
-	for _, m := range methods {
-		uh.methods[strings.ToUpper(m)] = ctrl
-	}
+	return uh
 }
 
 //Middlewares add middleware to route
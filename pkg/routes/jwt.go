@@ -0,0 +1,262 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/signature"
+)
+
+type jwtClaimsKey struct{}
+
+//jwtAlgorithms mapping of JWS algorithms to signature algorithms
+var jwtAlgorithms = map[string]string{
+	"HS256": "hmac-sha256",
+	"HS512": "hmac-sha512",
+	"RS256": signature.AlgRSAV15SHA256,
+	"PS512": signature.AlgRSAPSSSHA512,
+	"ES256": signature.AlgECDSAP256SHA256,
+	"ES384": signature.AlgECDSAP384SHA384,
+	"EdDSA": signature.AlgEd25519,
+}
+
+//JWTConfig model
+type JWTConfig struct {
+	Issuer   []string      `yaml:"issuer"`
+	Audience []string      `yaml:"audience"`
+	Leeway   time.Duration `yaml:"leeway"`
+	//AllowNoExp accept tokens without `exp` claim, such tokens never expire
+	AllowNoExp bool `yaml:"allow_no_exp"`
+	//Algorithms allowed JWS algorithms, all supported if empty: HS256, HS512, RS256, PS512, ES256, ES384, EdDSA
+	Algorithms []string `yaml:"algorithms"`
+}
+
+//JWTMiddleware validation of JWT from `Authorization: Bearer` header,
+//keys are searched in storage by `kid` (HMAC secrets or keys loaded by signature.JWKSLoader),
+//claims and principal are set to context
+func JWTMiddleware(conf JWTConfig, store *signature.Storage) func(c CtrlFunc) CtrlFunc {
+	algs := jwtAlgorithms
+	if len(conf.Algorithms) > 0 {
+		algs = make(map[string]string, len(conf.Algorithms))
+		for _, name := range conf.Algorithms {
+			if alg, ok := jwtAlgorithms[name]; ok {
+				algs[name] = alg
+			}
+		}
+	}
+
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r)
+			if len(token) == 0 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			claims, err := parseJWT(token, store, algs)
+			if err == nil {
+				err = claims.validate(conf, time.Now())
+			}
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			ctx := context.WithValue(r.Context(), jwtClaimsKey{}, claims)
+			c(w, r.WithContext(WithPrincipal(ctx, claims.Principal())))
+		}
+	}
+}
+
+//JWTClaims getting claims of validated JWT for current request
+func JWTClaims(r *http.Request) (*Claims, bool) {
+	v, ok := r.Context().Value(jwtClaimsKey{}).(*Claims)
+	return v, ok
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) < 7 || !strings.EqualFold(h[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(h[7:])
+}
+
+func parseJWT(token string, store *signature.Storage, algs map[string]string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errs.ErrInvalidToken
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	var head struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err = json.Unmarshal(b, &head); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	alg, ok := algs[head.Alg]
+	if !ok {
+		return nil, errors.WrapMessage(errs.ErrInvalidToken, "unsupported algorithm `%s`", head.Alg)
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	input := token[:len(parts[0])+1+len(parts[1])]
+	if _, err = store.Verify(head.Kid, alg, []byte(input), hex.EncodeToString(sign)); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	claims := &Claims{raw: payload}
+	if err = json.Unmarshal(payload, claims); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	if err = json.Unmarshal(payload, &claims.values); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//Claims model of JWT claims
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+
+	raw    []byte
+	values map[string]interface{}
+}
+
+//Audience claim as string or list of strings
+type Audience []string
+
+//UnmarshalJSON json unmarshaler
+func (v *Audience) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*v = Audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*v = list
+	return nil
+}
+
+func (v *Claims) validate(conf JWTConfig, now time.Time) error {
+	if v.ExpiresAt <= 0 && !conf.AllowNoExp {
+		return errors.WrapMessage(errs.ErrInvalidToken, "token has no expiration")
+	}
+	if v.ExpiresAt > 0 && now.After(time.Unix(v.ExpiresAt, 0).Add(conf.Leeway)) {
+		return errors.WrapMessage(errs.ErrInvalidToken, "token expired")
+	}
+	if v.NotBefore > 0 && now.Add(conf.Leeway).Before(time.Unix(v.NotBefore, 0)) {
+		return errors.WrapMessage(errs.ErrInvalidToken, "token is not valid yet")
+	}
+	if len(conf.Issuer) > 0 && !containsAny(conf.Issuer, v.Issuer) {
+		return errors.WrapMessage(errs.ErrInvalidToken, "invalid issuer")
+	}
+	if len(conf.Audience) > 0 && !containsAny(conf.Audience, v.Audience...) {
+		return errors.WrapMessage(errs.ErrInvalidToken, "invalid audience")
+	}
+	return nil
+}
+
+//Principal getting principal from claims: sub, scope (or scp) and roles
+func (v *Claims) Principal() *Principal {
+	p := &Principal{ID: v.Subject}
+	p.Roles, _ = v.Strings("roles")
+	if scopes, ok := v.Strings("scope"); ok {
+		p.Scopes = scopes
+	} else {
+		p.Scopes, _ = v.Strings("scp")
+	}
+	return p
+}
+
+//Decode unmarshal all claims to custom model
+func (v *Claims) Decode(out interface{}) error {
+	return json.Unmarshal(v.raw, out)
+}
+
+//Has claim exists
+func (v *Claims) Has(key string) bool {
+	_, ok := v.values[key]
+	return ok
+}
+
+//String getting claim as string
+func (v *Claims) String(key string) (string, bool) {
+	s, ok := v.values[key].(string)
+	return s, ok
+}
+
+//Int64 getting claim as integer
+func (v *Claims) Int64(key string) (int64, bool) {
+	f, ok := v.values[key].(float64)
+	return int64(f), ok
+}
+
+//Bool getting claim as boolean
+func (v *Claims) Bool(key string) (bool, bool) {
+	b, ok := v.values[key].(bool)
+	return b, ok
+}
+
+//Strings getting claim as list of strings, space separated string is split (example: scope)
+func (v *Claims) Strings(key string) ([]string, bool) {
+	switch val := v.values[key].(type) {
+	case string:
+		return strings.Fields(val), true
+	case []interface{}:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, s)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func containsAny(list []string, values ...string) bool {
+	for _, a := range list {
+		for _, b := range values {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
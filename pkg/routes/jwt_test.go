@@ -0,0 +1,93 @@
+package routes
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJWT(s signature.SignGetter, alg, payload string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	input := enc([]byte(`{"alg":"`+alg+`","typ":"JWT","kid":"`+s.ID()+`"}`)) + "." + enc([]byte(payload))
+	return input + "." + enc(s.Create([]byte(input)))
+}
+
+func TestUnit_JWTMiddleware(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	hs := signature.NewSHA256("hs", "secret")
+	ed := signature.NewEd25519("ed", edKey)
+
+	store := signature.NewStorage()
+	store.Add(hs)
+	store.Add(signature.NewEd25519Verifier("ed", edKey.Public().(ed25519.PublicKey)))
+
+	var claims *Claims
+	midd := JWTMiddleware(JWTConfig{
+		Issuer:   []string{"auth"},
+		Audience: []string{"api"},
+		Leeway:   time.Minute,
+	}, store)(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ = JWTClaims(r)
+	})
+
+	call := func(token string) int {
+		claims = nil
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		midd(rec, req)
+		return rec.Code
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := `{"iss":"auth","sub":"user1","aud":["api","web"],"scope":"read write","admin":true,"level":3,"exp":` +
+		strconv.FormatInt(exp, 10) + `}`
+
+	require.Equal(t, http.StatusOK, call(newTestJWT(hs, "HS256", payload)))
+	require.NotNil(t, claims)
+	require.Equal(t, "user1", claims.Subject)
+	require.Equal(t, Audience{"api", "web"}, claims.Audience)
+	scope, ok := claims.Strings("scope")
+	require.True(t, ok)
+	require.Equal(t, []string{"read", "write"}, scope)
+	require.Equal(t, &Principal{ID: "user1", Scopes: []string{"read", "write"}}, claims.Principal())
+	admin, ok := claims.Bool("admin")
+	require.True(t, ok && admin)
+	level, ok := claims.Int64("level")
+	require.True(t, ok)
+	require.Equal(t, int64(3), level)
+	var custom struct {
+		Level int `json:"level"`
+	}
+	require.NoError(t, claims.Decode(&custom))
+	require.Equal(t, 3, custom.Level)
+
+	require.Equal(t, http.StatusOK, call(newTestJWT(ed, "EdDSA", `{"iss":"auth","aud":"api","exp":`+strconv.FormatInt(exp, 10)+`}`)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(ed, "EdDSA", `{"iss":"auth","aud":"api"}`)))
+	require.NoError(t, (&Claims{}).validate(JWTConfig{AllowNoExp: true}, time.Now()))
+	require.Equal(t, http.StatusUnauthorized, call(""))
+	require.Equal(t, http.StatusUnauthorized, call("aaa.bbb.ccc"))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "EdDSA", payload)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "none", payload)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(signature.NewSHA256("hs", "other"), "HS256", payload)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "HS256", `{"iss":"other","aud":"api"}`)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "HS256", `{"iss":"auth","aud":"other"}`)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "HS256",
+		`{"iss":"auth","aud":"api","exp":`+strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)+`}`)))
+	require.Equal(t, http.StatusOK, call(newTestJWT(hs, "HS256",
+		`{"iss":"auth","aud":"api","exp":`+strconv.FormatInt(time.Now().Add(-30*time.Second).Unix(), 10)+`}`)))
+	require.Equal(t, http.StatusUnauthorized, call(newTestJWT(hs, "HS256",
+		`{"iss":"auth","aud":"api","nbf":`+strconv.FormatInt(time.Now().Add(2*time.Minute).Unix(), 10)+`}`)))
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/version"
 )
 
 var _ http.Handler = (*Router)(nil)
@@ -13,6 +14,7 @@ var _ http.Handler = (*Router)(nil)
 //Router model
 type Router struct {
 	handler *handler
+	version *VersionConfig
 	lock    sync.RWMutex
 }
 
@@ -30,6 +32,22 @@ func (v *Router) Route(path string, ctrl CtrlFunc, methods ...string) {
 	v.lock.Unlock()
 }
 
+//RouteVersion add new route for API version,
+//request is dispatched to the highest registered version less or equal to requested
+func (v *Router) RouteVersion(ver uint64, path string, ctrl CtrlFunc, methods ...string) {
+	v.lock.Lock()
+	v.handler.RouteVersion(ver, path, ctrl, methods)
+	v.lock.Unlock()
+}
+
+//Versioning setting source of API version for versioned routes
+func (v *Router) Versioning(conf VersionConfig) {
+	conf.validate()
+	v.lock.Lock()
+	v.version = &conf
+	v.lock.Unlock()
+}
+
 //Global add global middlewares
 func (v *Router) Global(middlewares ...MiddlFunc) {
 	v.lock.Lock()
@@ -56,12 +74,20 @@ func (v *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	v.lock.RLock()
 	defer v.lock.RUnlock()
 
-	code, next, vars, midd := v.handler.Match(r.URL.Path, r.Method)
+	path, ctx := r.URL.Path, r.Context()
+	if v.version != nil {
+		req := versionRequest{conf: v.version}
+		req.version, path = v.version.decode(r)
+		ctx = context.WithValue(ctx, versionRequestKey{}, req)
+	} else {
+		ctx = context.WithValue(ctx, versionRequestKey{}, versionRequest{version: version.Decode(r.Header)})
+	}
+
+	code, next, vars, midd := v.handler.Match(path, r.Method)
 	if code != http.StatusOK {
 		next = codeHandler(code)
 	}
 
-	ctx := r.Context()
 	for key, val := range vars {
 		ctx = context.WithValue(ctx, internal.VarsKey(key), val)
 	}
@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type cspNonceKey struct{}
+
+//SecurityConfig model
+type SecurityConfig struct {
+	HSTS               HSTSConfig `yaml:"hsts"`
+	ContentTypeNosniff bool       `yaml:"content_type_nosniff"`
+	FrameOptions       string     `yaml:"frame_options"`
+	ReferrerPolicy     string     `yaml:"referrer_policy"`
+	PermissionsPolicy  []string   `yaml:"permissions_policy"`
+	CSP                CSPConfig  `yaml:"csp"`
+}
+
+//HSTSConfig model of Strict-Transport-Security
+type HSTSConfig struct {
+	MaxAge            int  `yaml:"max_age"`
+	IncludeSubDomains bool `yaml:"include_subdomains"`
+	Preload           bool `yaml:"preload"`
+}
+
+//CSPConfig model of Content-Security-Policy
+type CSPConfig struct {
+	Directives map[string][]string `yaml:"directives"`
+	//Nonce list of directives which get the per-request nonce, example: script-src, style-src
+	Nonce      []string `yaml:"nonce"`
+	ReportOnly bool     `yaml:"report_only"`
+}
+
+//SecurityMiddleware setting security headers
+func SecurityMiddleware(conf SecurityConfig) func(c CtrlFunc) CtrlFunc {
+	h := make(http.Header)
+	if conf.HSTS.MaxAge > 0 {
+		v := "max-age=" + strconv.FormatInt(int64(conf.HSTS.MaxAge), 10)
+		if conf.HSTS.IncludeSubDomains {
+			v += "; includeSubDomains"
+		}
+		if conf.HSTS.Preload {
+			v += "; preload"
+		}
+		h.Set("Strict-Transport-Security", v)
+	}
+	if conf.ContentTypeNosniff {
+		h.Set("X-Content-Type-Options", "nosniff")
+	}
+	if len(conf.FrameOptions) > 0 {
+		h.Set("X-Frame-Options", conf.FrameOptions)
+	}
+	if len(conf.ReferrerPolicy) > 0 {
+		h.Set("Referrer-Policy", conf.ReferrerPolicy)
+	}
+	if len(conf.PermissionsPolicy) > 0 {
+		h.Set("Permissions-Policy", strings.Join(conf.PermissionsPolicy, ", "))
+	}
+
+	csp := NewCSP(conf.CSP)
+	cspHeader := "Content-Security-Policy"
+	if conf.CSP.ReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for key := range h {
+				w.Header().Set(key, h.Get(key))
+			}
+			if csp.Empty() {
+				c(w, r)
+				return
+			}
+			if !csp.HasNonce() {
+				w.Header().Set(cspHeader, csp.Build(""))
+				c(w, r)
+				return
+			}
+			nonce, err := newNonce()
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set(cspHeader, csp.Build(nonce))
+			c(w, r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, nonce)))
+		}
+	}
+}
+
+//CSPNonce getting nonce of Content-Security-Policy for current request
+func CSPNonce(r *http.Request) string {
+	if v, ok := r.Context().Value(cspNonceKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type cspDirective struct {
+	name   string
+	values string
+	nonce  bool
+}
+
+//CSP builder of Content-Security-Policy header
+type CSP struct {
+	list  []cspDirective
+	nonce bool
+}
+
+//NewCSP init builder from config
+func NewCSP(conf CSPConfig) *CSP {
+	dirs := make(map[string][]string, len(conf.Directives)+len(conf.Nonce))
+	for name, values := range conf.Directives {
+		name = strings.ToLower(name)
+		dirs[name] = append(dirs[name], values...)
+	}
+	nonces := make(map[string]struct{}, len(conf.Nonce))
+	for _, name := range conf.Nonce {
+		name = strings.ToLower(name)
+		nonces[name] = struct{}{}
+		if _, ok := dirs[name]; !ok {
+			dirs[name] = nil
+		}
+	}
+
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	v := &CSP{list: make([]cspDirective, 0, len(names))}
+	for _, name := range names {
+		_, isNonce := nonces[name]
+		v.list = append(v.list, cspDirective{
+			name:   name,
+			values: strings.Join(dirs[name], " "),
+			nonce:  isNonce,
+		})
+		v.nonce = v.nonce || isNonce
+	}
+	return v
+}
+
+//Empty policy has no directives
+func (v *CSP) Empty() bool {
+	return len(v.list) == 0
+}
+
+//HasNonce policy requires per-request nonce
+func (v *CSP) HasNonce() bool {
+	return v.nonce
+}
+
+//Build getting header value with nonce
+func (v *CSP) Build(nonce string) string {
+	var sb strings.Builder
+	for i, d := range v.list {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(d.name)
+		if len(d.values) > 0 {
+			sb.WriteString(" ")
+			sb.WriteString(d.values)
+		}
+		if d.nonce && len(nonce) > 0 {
+			sb.WriteString(" 'nonce-")
+			sb.WriteString(nonce)
+			sb.WriteString("'")
+		}
+	}
+	return sb.String()
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
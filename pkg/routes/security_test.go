@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_SecurityMiddleware(t *testing.T) {
+	nonce := ""
+	rec := httptest.NewRecorder()
+	SecurityMiddleware(SecurityConfig{
+		HSTS:               HSTSConfig{MaxAge: 100, IncludeSubDomains: true},
+		ContentTypeNosniff: true,
+		FrameOptions:       "DENY",
+		ReferrerPolicy:     "no-referrer",
+		PermissionsPolicy:  []string{"geolocation=()", "camera=()"},
+		CSP: CSPConfig{
+			Directives: map[string][]string{
+				"default-src": {"'self'"},
+				"script-src":  {"'self'", "cdn.example.com"},
+			},
+			Nonce: []string{"script-src", "style-src"},
+		},
+	})(func(w http.ResponseWriter, r *http.Request) {
+		nonce = CSPNonce(r)
+	})(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	h := rec.Result().Header
+	require.NotEmpty(t, nonce)
+	require.Equal(t, "max-age=100; includeSubDomains", h.Get("Strict-Transport-Security"))
+	require.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+	require.Equal(t, "DENY", h.Get("X-Frame-Options"))
+	require.Equal(t, "no-referrer", h.Get("Referrer-Policy"))
+	require.Equal(t, "geolocation=(), camera=()", h.Get("Permissions-Policy"))
+	require.Equal(t,
+		"default-src 'self'; script-src 'self' cdn.example.com 'nonce-"+nonce+"'; style-src 'nonce-"+nonce+"'",
+		h.Get("Content-Security-Policy"))
+}
+
+func TestUnit_NewCSP(t *testing.T) {
+	csp := NewCSP(CSPConfig{})
+	require.True(t, csp.Empty())
+	require.False(t, csp.HasNonce())
+
+	csp = NewCSP(CSPConfig{Directives: map[string][]string{"Upgrade-Insecure-Requests": nil, "img-src": {"*"}}})
+	require.False(t, csp.HasNonce())
+	require.Equal(t, "img-src *; upgrade-insecure-requests", csp.Build("aaa"))
+}
@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/signature"
+)
+
+type signatureKeyIDKey struct{}
+
+//DefaultSignatureBodyLimit max size of request body for signature validation
+const DefaultSignatureBodyLimit int64 = 10 << 20
+
+//SignatureMiddleware validation of request body signature from header
+//with body limited by DefaultSignatureBodyLimit
+//  401 - header is missing, invalid or key ID has no active versions
+//  403 - algorithm does not match the key or hash is invalid for all active versions
+//  413 - body is larger than limit
+func SignatureMiddleware(store *signature.Storage) func(c CtrlFunc) CtrlFunc {
+	return SignatureLimitMiddleware(store, DefaultSignatureBodyLimit)
+}
+
+//SignatureLimitMiddleware validation of request body signature from header,
+//key is checked before body is read, body is read up to limit bytes
+func SignatureLimitMiddleware(store *signature.Storage, limit int64) func(c CtrlFunc) CtrlFunc {
+	if limit <= 0 {
+		limit = DefaultSignatureBodyLimit
+	}
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			data, err := signature.Decode(r.Header)
+			if err != nil || store.Get(data.ID) == nil {
+				signatureUnauthorized(w)
+				return
+			}
+			var body []byte
+			if r.Body != nil {
+				if body, err = internal.ReadAll(http.MaxBytesReader(w, r.Body, limit)); err != nil {
+					if r.ContentLength > limit || int64(len(body)) >= limit {
+						w.WriteHeader(http.StatusRequestEntityTooLarge)
+						return
+					}
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+			if _, err = store.Verify(data.ID, data.Alg, body, data.Hash); err != nil {
+				if errors.Is(err, errs.ErrSignatureKeyNotFound) {
+					signatureUnauthorized(w)
+					return
+				}
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			ctx := context.WithValue(r.Context(), signatureKeyIDKey{}, data.ID)
+			c(w, r.WithContext(WithPrincipal(ctx, &Principal{ID: data.ID})))
+		}
+	}
+}
+
+//SignatureKeyID getting key ID of validated request signature
+func SignatureKeyID(r *http.Request) string {
+	if v, ok := r.Context().Value(signatureKeyIDKey{}).(string); ok {
+		return v
+	}
+	return ""
+}
+
+func signatureUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", signature.SignHeader)
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+//SignResponseMiddleware signing of response with `Signature` header, status, method and
+//request target are signed with body (see signature.ResponseData),
+//response is buffered until controller is completed, so signed routes can not stream:
+//writer does not implement http.Flusher and hijacking (example: websocket upgrade) returns errs.ErrHijackNotSupported
+func SignResponseMiddleware(s signature.SignGetter) func(c CtrlFunc) CtrlFunc {
+	return func(c CtrlFunc) CtrlFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			method, target := r.Method, r.URL.RequestURI()
+			bw := &bufferWriter{ResponseWriter: w, code: http.StatusOK}
+			c(bw, r)
+			signature.Encode(w.Header(), s, signature.ResponseData(bw.code, method, target, bw.buf.Bytes()))
+			w.WriteHeader(bw.code)
+			w.Write(bw.buf.Bytes()) //nolint: errcheck
+		}
+	}
+}
+
+//bufferWriter buffering of response
+type bufferWriter struct {
+	http.ResponseWriter
+	code  int
+	wrote bool
+	buf   bytes.Buffer
+}
+
+func (v *bufferWriter) WriteHeader(code int) {
+	if !v.wrote {
+		v.code, v.wrote = code, true
+	}
+}
+
+func (v *bufferWriter) Write(b []byte) (int, error) {
+	v.wrote = true
+	return v.buf.Write(b)
+}
+
+//Hijack connection of buffered response can not be taken over, response must be signed
+func (v *bufferWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errs.ErrHijackNotSupported
+}
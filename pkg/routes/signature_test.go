@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_SignatureMiddleware(t *testing.T) {
+	store := signature.NewStorage()
+	store.Add(signature.NewSHA256("1", "secret"))
+
+	var keyID string
+	var principal *Principal
+	var body []byte
+	midd := SignatureMiddleware(store)(func(w http.ResponseWriter, r *http.Request) {
+		keyID = SignatureKeyID(r)
+		principal, _ = GetPrincipal(r)
+		body, _ = internal.ReadAll(r.Body) //nolint: errcheck
+	})
+
+	newReq := func(s signature.SignGetter, sb, b []byte) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+		if s != nil {
+			signature.Encode(req.Header, s, sb)
+		}
+		return req
+	}
+
+	rec := httptest.NewRecorder()
+	midd(rec, newReq(nil, nil, []byte("hello")))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Equal(t, "Signature", rec.Header().Get("WWW-Authenticate"))
+
+	rec = httptest.NewRecorder()
+	midd(rec, newReq(signature.NewSHA256("2", "secret"), []byte("hello"), []byte("hello")))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	midd(rec, newReq(signature.NewMD5("1", "secret"), []byte("hello"), []byte("hello")))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	midd(rec, newReq(signature.NewSHA256("1", "secret"), []byte("hello"), []byte("world")))
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = httptest.NewRecorder()
+	midd(rec, newReq(signature.NewSHA256("1", "secret"), []byte("hello"), []byte("hello")))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "1", keyID)
+	require.Equal(t, &Principal{ID: "1"}, principal)
+	require.Equal(t, []byte("hello"), body)
+}
+
+func TestUnit_SignatureLimitMiddleware(t *testing.T) {
+	store := signature.NewStorage()
+	s := signature.NewSHA256("1", "secret")
+	store.Add(s)
+
+	called := false
+	midd := SignatureLimitMiddleware(store, 4)(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("hello")))
+	signature.Encode(req.Header, s, []byte("hello"))
+	rec := httptest.NewRecorder()
+	midd(rec, req)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("hello")))
+	signature.Encode(req.Header, signature.NewSHA256("2", "secret"), []byte("hello"))
+	rec = httptest.NewRecorder()
+	midd(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("hell")))
+	signature.Encode(req.Header, s, []byte("hell"))
+	rec = httptest.NewRecorder()
+	midd(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, called)
+}
+
+func TestUnit_SignResponseMiddleware(t *testing.T) {
+	s := signature.NewSHA256("1", "secret")
+	midd := SignResponseMiddleware(s)(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello")) //nolint: errcheck
+	})
+
+	rec := httptest.NewRecorder()
+	midd(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("X-Test"))
+	require.Equal(t, "hello", rec.Body.String())
+
+	data, err := signature.Decode(rec.Header())
+	require.NoError(t, err)
+	require.Equal(t, "1", data.ID)
+	require.True(t, s.Validate(signature.ResponseData(http.StatusCreated, http.MethodGet, "/", []byte("hello")), data.Hash))
+	require.False(t, s.Validate([]byte("hello"), data.Hash))
+	require.False(t, s.Validate(signature.ResponseData(http.StatusOK, http.MethodGet, "/", []byte("hello")), data.Hash))
+	require.False(t, s.Validate(signature.ResponseData(http.StatusCreated, http.MethodGet, "/other", []byte("hello")), data.Hash))
+}
+
+func TestUnit_SignResponseMiddlewareHijack(t *testing.T) {
+	midd := SignResponseMiddleware(signature.NewSHA256("1", "secret"))(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(http.Flusher)
+		require.False(t, ok)
+		_, _, err := w.(http.Hijacker).Hijack()
+		require.ErrorIs(t, err, errs.ErrHijackNotSupported)
+		w.WriteHeader(http.StatusNotImplemented)
+	})
+
+	rec := httptest.NewRecorder()
+	midd(rec, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	require.Equal(t, http.StatusNotImplemented, rec.Code)
+}
@@ -0,0 +1,207 @@
+package routes
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/version"
+)
+
+const (
+	VersionSourceAccept = "accept"
+	VersionSourceHeader = "header"
+	VersionSourcePath   = "path"
+
+	defaultVersionHeader = "X-API-Version"
+)
+
+var versionSuffixes = []string{"json", "xml", "cbor"}
+
+type (
+	versionRequestKey struct{}
+	versionKey        struct{}
+)
+
+//VersionConfig model
+type VersionConfig struct {
+	//Source of version: accept (default), header, path
+	Source string `yaml:"source"`
+	//Header name for header source
+	Header string `yaml:"header"`
+	//Default version if request has no version, the highest version is used if zero
+	Default uint64 `yaml:"default"`
+	//Vendor name for response media type: application/vnd.<vendor>.v2+json
+	Vendor string `yaml:"vendor"`
+	//Deprecated versions, deprecation and sunset headers are set to response
+	Deprecated map[uint64]version.Deprecation `yaml:"deprecated"`
+}
+
+//versionRequest requested version and config of versioning
+type versionRequest struct {
+	version uint64
+	conf    *VersionConfig
+}
+
+func (v *VersionConfig) validate() {
+	v.Source = strings.ToLower(v.Source)
+	if v.Source != VersionSourceHeader && v.Source != VersionSourcePath {
+		v.Source = VersionSourceAccept
+	}
+	if len(v.Header) == 0 {
+		v.Header = defaultVersionHeader
+	}
+}
+
+//decode getting requested version and path without version prefix
+func (v *VersionConfig) decode(r *http.Request) (uint64, string) {
+	path, ver := r.URL.Path, uint64(0)
+	switch v.Source {
+	case VersionSourcePath:
+		uris := split(path)
+		//version segment must have prefix, otherwise numeric IDs are taken as versions: /v2/users, not /2/users
+		if len(uris) > 0 && len(uris[0]) > 1 && (uris[0][0] == 'v' || uris[0][0] == 'V') {
+			if n, ok := parseVersion(uris[0]); ok {
+				ver, path = n, separate+strings.Join(uris[1:], separate)
+			}
+		}
+	case VersionSourceHeader:
+		ver, _ = parseVersion(r.Header.Get(v.Header))
+	default:
+		ver = version.Decode(r.Header)
+	}
+	if ver == 0 {
+		ver = v.Default
+	}
+	return ver, path
+}
+
+func parseVersion(v string) (uint64, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+	ver, ok := version.ParseVersion(v)
+	return ver.Major, ok && ver.Major > 0
+}
+
+//APIVersion getting version of API selected for current request
+func APIVersion(r *http.Request) uint64 {
+	if v, ok := r.Context().Value(versionKey{}).(uint64); ok {
+		return v
+	}
+	return 0
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//versioned dispatcher of controllers by API version
+type versioned struct {
+	list map[uint64]CtrlFunc
+	keys []uint64
+}
+
+func newVersioned() *versioned {
+	return &versioned{
+		list: make(map[uint64]CtrlFunc),
+		keys: make([]uint64, 0, 1),
+	}
+}
+
+func (v *versioned) Add(ver uint64, ctrl CtrlFunc) {
+	if _, ok := v.list[ver]; !ok {
+		v.keys = append(v.keys, ver)
+		sort.Slice(v.keys, func(i, j int) bool { return v.keys[i] < v.keys[j] })
+	}
+	v.list[ver] = ctrl
+}
+
+//Match getting controller with the highest version less or equal to requested
+func (v *versioned) Match(ver uint64) (uint64, CtrlFunc, bool) {
+	if len(v.keys) == 0 {
+		return 0, nil, false
+	}
+	if ver == 0 {
+		ver = v.keys[len(v.keys)-1]
+	}
+	i := sort.Search(len(v.keys), func(i int) bool { return v.keys[i] > ver })
+	if i == 0 {
+		return 0, nil, false
+	}
+	return v.keys[i-1], v.list[v.keys[i-1]], true
+}
+
+func (v *versioned) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, _ := r.Context().Value(versionRequestKey{}).(versionRequest)
+	//response depends on header with version, it must be a part of cache key
+	switch {
+	case req.conf == nil || req.conf.Source == VersionSourceAccept:
+		w.Header().Add("Vary", "Accept")
+	case req.conf.Source == VersionSourceHeader:
+		w.Header().Add("Vary", req.conf.Header)
+	}
+	ver, ctrl, ok := v.Match(req.version)
+	if !ok {
+		w.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+	vendor := ""
+	if req.conf != nil {
+		vendor = req.conf.Vendor
+		if d, ok := req.conf.Deprecated[ver]; ok {
+			d.Encode(w.Header())
+		}
+	}
+	ctx := context.WithValue(r.Context(), versionKey{}, ver)
+	ctrl(&versionWriter{ResponseWriter: w, version: ver, vendor: vendor}, r.WithContext(ctx))
+}
+
+//versionWriter replaces JSON content type with versioned media type
+type versionWriter struct {
+	http.ResponseWriter
+	version uint64
+	vendor  string
+	wrote   bool
+}
+
+func (v *versionWriter) WriteHeader(code int) {
+	if !v.wrote {
+		v.wrote = true
+		ct := v.Header().Get("Content-Type")
+		for _, suffix := range versionSuffixes {
+			if strings.HasPrefix(ct, "application/"+suffix) {
+				m := version.MediaType{Vendor: v.vendor, Version: version.Version{Major: v.version}, Suffix: suffix}
+				v.Header().Set("Content-Type", m.String())
+				break
+			}
+		}
+	}
+	v.ResponseWriter.WriteHeader(code)
+}
+
+func (v *versionWriter) Write(b []byte) (int, error) {
+	if !v.wrote {
+		v.WriteHeader(http.StatusOK)
+	}
+	return v.ResponseWriter.Write(b)
+}
+
+func (v *versionWriter) Flush() {
+	if !v.wrote {
+		v.WriteHeader(http.StatusOK)
+	}
+	if f, ok := v.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (v *versionWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := v.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errs.ErrHijackNotSupported
+	}
+	return h.Hijack()
+}
@@ -0,0 +1,112 @@
+package routes_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/httputil/enc"
+	"github.com/deweppro/go-http/pkg/routes"
+	"github.com/deweppro/go-http/pkg/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_RouteVersion(t *testing.T) {
+	ctrl := func(w http.ResponseWriter, r *http.Request) {
+		enc.JSON(w, map[string]string{"v": strconv.FormatUint(routes.APIVersion(r), 10)})
+	}
+	newRouter := func() *routes.Router {
+		r := routes.NewRouter()
+		r.RouteVersion(2, "/users", ctrl, http.MethodGet)
+		r.RouteVersion(4, "/users", ctrl, http.MethodGet)
+		r.Route("/ping", func(w http.ResponseWriter, r *http.Request) {}, http.MethodGet)
+		r.Route("/{id}/comments", func(w http.ResponseWriter, r *http.Request) {}, http.MethodGet)
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		conf   *routes.VersionConfig
+		path   string
+		header http.Header
+		code   int
+		ver    string
+		vary   string
+	}{
+		{name: "Case1", path: "/users", code: http.StatusOK, ver: "4", vary: "Accept"},
+		{name: "Case2", path: "/users", header: http.Header{"Accept": {"application/vnd.v3+json"}}, code: http.StatusOK, ver: "2", vary: "Accept"},
+		{name: "Case3", path: "/users", header: http.Header{"Accept": {"application/vnd.v1+json"}}, code: http.StatusNotAcceptable, vary: "Accept"},
+		{name: "Case4", path: "/users", header: http.Header{"Accept": {"application/vnd.v9+json"}}, code: http.StatusOK, ver: "4", vary: "Accept"},
+		{name: "Case5", conf: &routes.VersionConfig{Default: 2}, path: "/users", code: http.StatusOK, ver: "2", vary: "Accept"},
+		{name: "Case6", conf: &routes.VersionConfig{Source: "header"}, path: "/users", header: http.Header{"X-Api-Version": {"v2"}}, code: http.StatusOK, ver: "2", vary: "X-API-Version"},
+		{name: "Case7", conf: &routes.VersionConfig{Source: "path"}, path: "/v3/users", code: http.StatusOK, ver: "2"},
+		{name: "Case8", conf: &routes.VersionConfig{Source: "path"}, path: "/users", code: http.StatusOK, ver: "4"},
+		{name: "Case9", conf: &routes.VersionConfig{Source: "path"}, path: "/v1/ping", code: http.StatusOK},
+		{name: "Case11", conf: &routes.VersionConfig{Source: "path"}, path: "/123/comments", code: http.StatusOK},
+		{name: "Case12", conf: &routes.VersionConfig{Source: "path"}, path: "/2/users", code: http.StatusNotFound},
+		{name: "Case10", path: "/users", header: http.Header{"Accept": {"text/html, application/vnd.acme.v2.1+json;q=0.8"}}, code: http.StatusOK, ver: "2", vary: "Accept"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter()
+			if tt.conf != nil {
+				r.Versioning(*tt.conf)
+			}
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.code, w.Code)
+			require.Equal(t, tt.vary, w.Header().Get("Vary"))
+			if len(tt.ver) > 0 {
+				require.Equal(t, "application/vnd.v"+tt.ver+"+json", w.Header().Get("Content-Type"))
+				require.Equal(t, `{"v":"`+tt.ver+`"}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUnit_RouteVersionDeprecated(t *testing.T) {
+	r := routes.NewRouter()
+	r.RouteVersion(1, "/users", func(w http.ResponseWriter, r *http.Request) { enc.JSON(w, "ok") }, http.MethodGet)
+	r.Versioning(routes.VersionConfig{
+		Vendor:     "acme",
+		Deprecated: map[uint64]version.Deprecation{1: {Date: time.Unix(1688169599, 0)}},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/vnd.acme.v1+json", w.Header().Get("Content-Type"))
+	require.Equal(t, "@1688169599", w.Header().Get("Deprecation"))
+}
+
+func TestUnit_RouteVersionHijack(t *testing.T) {
+	r := routes.NewRouter()
+	r.RouteVersion(1, "/ws", func(w http.ResponseWriter, r *http.Request) {
+		_, ok := w.(http.Hijacker)
+		require.True(t, ok)
+		_, ok = w.(http.Flusher)
+		require.True(t, ok)
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok") //nolint: errcheck
+		buf.Flush()                                                     //nolint: errcheck
+		conn.Close()                                                    //nolint: errcheck
+	}, http.MethodGet)
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ws")
+	require.NoError(t, err)
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "ok", string(b))
+}
@@ -0,0 +1,185 @@
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+)
+
+const (
+	AlgEd25519         = "ed25519"
+	AlgECDSAP256SHA256 = "ecdsa-p256-sha256"
+	AlgECDSAP384SHA384 = "ecdsa-p384-sha384"
+	AlgRSAPSSSHA512    = "rsa-pss-sha512"
+	AlgRSAV15SHA256    = "rsa-v1_5-sha256"
+)
+
+var _ SignGetter = (*AsymmetricSignature)(nil)
+
+//AsymmetricSignature model of signature with private/public key pair,
+//if private key is not set the signature can only validate
+type AsymmetricSignature struct {
+	id     string
+	alg    string
+	sign   func(b []byte) ([]byte, error)
+	verify func(b, sig []byte) bool
+}
+
+//NewEd25519 create sign ed25519
+func NewEd25519(id string, key ed25519.PrivateKey) *AsymmetricSignature {
+	v := NewEd25519Verifier(id, key.Public().(ed25519.PublicKey))
+	v.sign = func(b []byte) ([]byte, error) {
+		return ed25519.Sign(key, b), nil
+	}
+	return v
+}
+
+//NewEd25519Verifier create validator ed25519
+func NewEd25519Verifier(id string, key ed25519.PublicKey) *AsymmetricSignature {
+	return &AsymmetricSignature{
+		id:  id,
+		alg: AlgEd25519,
+		verify: func(b, sig []byte) bool {
+			return ed25519.Verify(key, b, sig)
+		},
+	}
+}
+
+//NewECDSA create sign ecdsa with curve P-256 or P-384
+func NewECDSA(id string, key *ecdsa.PrivateKey) *AsymmetricSignature {
+	v := NewECDSAVerifier(id, &key.PublicKey)
+	size := (key.Curve.Params().BitSize + 7) / 8
+	v.sign = func(b []byte) ([]byte, error) {
+		r, s, err := ecdsa.Sign(rand.Reader, key, ecdsaDigest(key.Curve, b))
+		if err != nil {
+			return nil, err
+		}
+		sig := make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+		return sig, nil
+	}
+	return v
+}
+
+//NewECDSAVerifier create validator ecdsa with curve P-256 or P-384
+func NewECDSAVerifier(id string, key *ecdsa.PublicKey) *AsymmetricSignature {
+	alg := AlgECDSAP256SHA256
+	if key.Curve == elliptic.P384() {
+		alg = AlgECDSAP384SHA384
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return &AsymmetricSignature{
+		id:  id,
+		alg: alg,
+		verify: func(b, sig []byte) bool {
+			if len(sig) != 2*size {
+				return false
+			}
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			return ecdsa.Verify(key, ecdsaDigest(key.Curve, b), r, s)
+		},
+	}
+}
+
+//NewRSAPSS create sign rsa-pss with sha512
+func NewRSAPSS(id string, key *rsa.PrivateKey) *AsymmetricSignature {
+	v := NewRSAPSSVerifier(id, &key.PublicKey)
+	v.sign = func(b []byte) ([]byte, error) {
+		h := sha512.Sum512(b)
+		return rsa.SignPSS(rand.Reader, key, crypto.SHA512, h[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+	return v
+}
+
+//NewRSAPSSVerifier create validator rsa-pss with sha512
+func NewRSAPSSVerifier(id string, key *rsa.PublicKey) *AsymmetricSignature {
+	return &AsymmetricSignature{
+		id:  id,
+		alg: AlgRSAPSSSHA512,
+		verify: func(b, sig []byte) bool {
+			h := sha512.Sum512(b)
+			return rsa.VerifyPSS(key, crypto.SHA512, h[:], sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		},
+	}
+}
+
+//NewRSA create sign rsa PKCS #1 v1.5 with sha256
+func NewRSA(id string, key *rsa.PrivateKey) *AsymmetricSignature {
+	v := NewRSAVerifier(id, &key.PublicKey)
+	v.sign = func(b []byte) ([]byte, error) {
+		h := sha256.Sum256(b)
+		return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	}
+	return v
+}
+
+//NewRSAVerifier create validator rsa PKCS #1 v1.5 with sha256
+func NewRSAVerifier(id string, key *rsa.PublicKey) *AsymmetricSignature {
+	return &AsymmetricSignature{
+		id:  id,
+		alg: AlgRSAV15SHA256,
+		verify: func(b, sig []byte) bool {
+			h := sha256.Sum256(b)
+			return rsa.VerifyPKCS1v15(key, crypto.SHA256, h[:], sig) == nil
+		},
+	}
+}
+
+//ID signature
+func (s *AsymmetricSignature) ID() string {
+	return s.id
+}
+
+//Algorithm getter
+func (s *AsymmetricSignature) Algorithm() string {
+	return s.alg
+}
+
+//CanSign private key is set
+func (s *AsymmetricSignature) CanSign() bool {
+	return s.sign != nil
+}
+
+//Create getting signature as bytes, returns nil if private key is not set
+func (s *AsymmetricSignature) Create(b []byte) []byte {
+	if s.sign == nil {
+		return nil
+	}
+	sig, err := s.sign(b)
+	if err != nil {
+		return nil
+	}
+	return sig
+}
+
+//CreateString getting signature as string
+func (s *AsymmetricSignature) CreateString(b []byte) string {
+	return hex.EncodeToString(s.Create(b))
+}
+
+//Validate signature
+func (s *AsymmetricSignature) Validate(b []byte, ex string) bool {
+	v, err := hex.DecodeString(ex)
+	if err != nil {
+		return false
+	}
+	return s.verify(b, v)
+}
+
+func ecdsaDigest(c elliptic.Curve, b []byte) []byte {
+	if c == elliptic.P384() {
+		h := sha512.Sum384(b)
+		return h[:]
+	}
+	h := sha256.Sum256(b)
+	return h[:]
+}
@@ -0,0 +1,148 @@
+package signature_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_AsymmetricSignature(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		signer *signature.AsymmetricSignature
+		public *signature.AsymmetricSignature
+		alg    string
+	}{
+		{
+			name:   "ed25519",
+			signer: signature.NewEd25519("1", edKey),
+			public: signature.NewEd25519Verifier("1", edKey.Public().(ed25519.PublicKey)),
+			alg:    "ed25519",
+		},
+		{
+			name:   "ecdsa",
+			signer: signature.NewECDSA("1", ecKey),
+			public: signature.NewECDSAVerifier("1", &ecKey.PublicKey),
+			alg:    "ecdsa-p256-sha256",
+		},
+		{
+			name:   "rsa-pss",
+			signer: signature.NewRSAPSS("1", rsaKey),
+			public: signature.NewRSAPSSVerifier("1", &rsaKey.PublicKey),
+			alg:    "rsa-pss-sha512",
+		},
+		{
+			name:   "rsa",
+			signer: signature.NewRSA("1", rsaKey),
+			public: signature.NewRSAVerifier("1", &rsaKey.PublicKey),
+			alg:    "rsa-v1_5-sha256",
+		},
+	}
+	body := []byte("hello")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.alg, tt.signer.Algorithm())
+			require.Equal(t, tt.alg, tt.public.Algorithm())
+			require.False(t, tt.public.CanSign())
+			require.Nil(t, tt.public.Create(body))
+
+			hash := tt.signer.CreateString(body)
+			require.True(t, tt.public.Validate(body, hash))
+			require.True(t, tt.signer.Validate(body, hash))
+			require.False(t, tt.public.Validate([]byte("world"), hash))
+			require.False(t, tt.public.Validate(body, "zz"))
+
+			store := signature.NewStorage()
+			store.Add(tt.public)
+			require.True(t, store.Get("1").Validate(body, hash))
+		})
+	}
+}
+
+func TestUnit_ParseKeyPEM(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	priv, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	signer, err := signature.ParsePrivateKeyPEM("1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: priv}))
+	require.NoError(t, err)
+	require.True(t, signer.CanSign())
+	verifier, err := signature.ParsePublicKeyPEM("1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+	require.NoError(t, err)
+	require.False(t, verifier.CanSign())
+	require.True(t, verifier.Validate([]byte("hello"), signer.CreateString([]byte("hello"))))
+
+	_, err = signature.ParsePublicKeyPEM("1", []byte("hello"))
+	require.Error(t, err)
+	_, err = signature.ParsePublicKeyPEM("1", pem.EncodeToMemory(&pem.Block{Type: "UNKNOWN", Bytes: pub}))
+	require.Error(t, err)
+}
+
+func TestUnit_ParseJWK(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	enc := base64.RawURLEncoding.EncodeToString
+
+	signer, err := signature.ParseJWK([]byte(fmt.Sprintf(
+		`{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"%s","d":"%s"}`, enc(pub), enc(key.Seed()))))
+	require.NoError(t, err)
+	require.Equal(t, "k1", signer.ID())
+	require.True(t, signer.CanSign())
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = signature.ParseJWK([]byte(fmt.Sprintf(
+		`{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"%s","d":"%s"}`, enc(other), enc(key.Seed()))))
+	require.Error(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	e := big.NewInt(int64(rsaKey.E)).Bytes()
+	rs, err := signature.ParseJWK([]byte(fmt.Sprintf(
+		`{"kty":"RSA","kid":"k5","n":"%s","e":"%s"}`, enc(rsaKey.N.Bytes()), enc(e))))
+	require.NoError(t, err)
+	require.Equal(t, signature.AlgRSAPSSSHA512, rs.Algorithm())
+	rs, err = signature.ParseJWK([]byte(fmt.Sprintf(
+		`{"kty":"RSA","kid":"k5","alg":"RS256","n":"%s","e":"%s"}`, enc(rsaKey.N.Bytes()), enc(e))))
+	require.NoError(t, err)
+	require.Equal(t, signature.AlgRSAV15SHA256, rs.Algorithm())
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	list, err := signature.ParseJWKSet([]byte(fmt.Sprintf(`{"keys":[
+		{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"%s"},
+		{"kty":"EC","crv":"P-256","kid":"k2","x":"%s","y":"%s"},
+		{"kty":"oct","kid":"k3","k":"aaa"},
+		{"kty":"OKP","crv":"Ed25519","kid":"k4","use":"enc","x":"%s"}
+	]}`, enc(pub), enc(ecKey.X.Bytes()), enc(ecKey.Y.Bytes()), enc(pub))))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(list))
+	require.Equal(t, "k1", list[0].ID())
+	require.Equal(t, "ed25519", list[0].Algorithm())
+	require.Equal(t, "k2", list[1].ID())
+	require.Equal(t, "ecdsa-p256-sha256", list[1].Algorithm())
+	require.True(t, list[0].Validate([]byte("hello"), signer.CreateString([]byte("hello"))))
+}
@@ -34,6 +34,13 @@ func Decode(h http.Header) (s Data, err error) {
 	return
 }
 
+//ResponseData data of signed response, status and request target are bound to body
+//so signed body can not be replayed as response to other request or with other status
+func ResponseData(status int, method, target string, body []byte) []byte {
+	head := fmt.Sprintf("%d %s %s\n", status, method, target)
+	return append([]byte(head), body...)
+}
+
 //Encode make and setting signature to header
 func Encode(h http.Header, s SignGetter, body []byte) {
 	h.Set(SignHeader, fmt.Sprintf(signValueTmpl, s.ID(), s.Algorithm(), s.CreateString(body)))
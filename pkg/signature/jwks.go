@@ -0,0 +1,121 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-logger"
+)
+
+const (
+	defaultJWKSTimeout = 5 * time.Second
+	maxJWKSSize        = 1 << 20
+)
+
+//JWKSLoader loading JSON Web Key Set from file or HTTP endpoint to storage with periodic refresh,
+//only keys added by the loader are replaced on refresh
+type JWKSLoader struct {
+	source string
+	store  *Storage
+	log    logger.Logger
+	cli    *http.Client
+	data   []byte
+	ids    map[string]struct{}
+	tick   *ticker
+	lock   sync.Mutex
+}
+
+//NewJWKSLoader init loader, source is a file path or http(s) URL
+func NewJWKSLoader(source string, interval time.Duration, store *Storage, log logger.Logger) *JWKSLoader {
+	return &JWKSLoader{
+		source: source,
+		store:  store,
+		log:    log,
+		cli:    &http.Client{Timeout: defaultJWKSTimeout},
+		ids:    make(map[string]struct{}),
+		tick:   newTicker(interval),
+	}
+}
+
+//Load reading key set and updating keys in storage
+func (v *JWKSLoader) Load() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	data, err := v.fetch()
+	if err != nil {
+		return err
+	}
+	if v.data != nil && bytes.Equal(v.data, data) {
+		return nil
+	}
+	list, err := ParseJWKSet(data)
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]struct{}, len(list))
+	for _, s := range list {
+		ids[s.ID()] = struct{}{}
+		v.store.Add(s)
+	}
+	for id := range v.ids {
+		if _, ok := ids[id]; !ok {
+			v.store.Del(id)
+		}
+	}
+	v.ids, v.data = ids, data
+	return nil
+}
+
+func (v *JWKSLoader) fetch() ([]byte, error) {
+	if !strings.HasPrefix(v.source, "http://") && !strings.HasPrefix(v.source, "https://") {
+		return ioutil.ReadFile(v.source)
+	}
+	ctx, cncl := context.WithTimeout(context.Background(), defaultJWKSTimeout)
+	defer cncl()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint: errcheck
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxJWKSSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxJWKSSize {
+		return nil, errors.WrapMessage(errs.ErrInvalidKey, "jwks response is larger than %d bytes", maxJWKSSize)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.WrapMessage(errs.ErrInvalidKey, "jwks response status %d", resp.StatusCode)
+	}
+	return b, nil
+}
+
+//Up loading keys and start refreshing
+func (v *JWKSLoader) Up() error {
+	return v.tick.start(v.source, v.Load, v.reload)
+}
+
+//Down stop refreshing
+func (v *JWKSLoader) Down() error {
+	return v.tick.stop(v.source)
+}
+
+func (v *JWKSLoader) reload() {
+	if err := v.Load(); err != nil {
+		v.log.WithFields(logger.Fields{"err": err.Error(), "source": v.source}).Errorf("jwks reload")
+	}
+}
@@ -0,0 +1,52 @@
+package signature_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/deweppro/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_JWKSLoader(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	x := base64.RawURLEncoding.EncodeToString(pub)
+
+	var kid int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k%d","x":"%s"}]}`, atomic.LoadInt64(&kid), x)
+	}))
+	defer srv.Close()
+
+	store := signature.NewStorage()
+	store.Add(signature.NewSHA256("hs", "secret"))
+
+	loader := signature.NewJWKSLoader(srv.URL, 0, store, logger.Default())
+	require.NoError(t, loader.Load())
+	require.Equal(t, 2, store.Count())
+	require.NotNil(t, store.Get("k0"))
+
+	atomic.StoreInt64(&kid, 1)
+	require.NoError(t, loader.Load())
+	require.Equal(t, 2, store.Count())
+	require.Nil(t, store.Get("k0"))
+	require.NotNil(t, store.Get("k1"))
+	require.NotNil(t, store.Get("hs"))
+
+	require.Error(t, signature.NewJWKSLoader(srv.URL+"/%%", 0, store, logger.Default()).Load())
+
+	large := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte(" "), 2<<20)) //nolint: errcheck
+	}))
+	defer large.Close()
+	require.Error(t, signature.NewJWKSLoader(large.URL, 0, store, logger.Default()).Load())
+}
@@ -0,0 +1,277 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+//defaultRSAAlg algorithm of RSA keys if it is not specified
+const defaultRSAAlg = AlgRSAPSSSHA512
+
+//ParsePrivateKeyPEM create sign from PEM encoded private key (PKCS #8, PKCS #1 or SEC 1),
+//RSA keys are used with rsa-pss-sha512
+func ParsePrivateKeyPEM(id string, data []byte) (*AsymmetricSignature, error) {
+	key, err := parsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	return newSigner(id, key, defaultRSAAlg)
+}
+
+func parsePrivateKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.WrapMessage(errs.ErrInvalidKey, "pem block not found")
+	}
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "pem type `%s`", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidKey)
+	}
+	return key, nil
+}
+
+func newSigner(id string, key interface{}, rsaAlg string) (*AsymmetricSignature, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return NewEd25519(id, k), nil
+	case *ecdsa.PrivateKey:
+		if !supportedCurve(k.Curve) {
+			return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "curve `%s`", k.Curve.Params().Name)
+		}
+		return NewECDSA(id, k), nil
+	case *rsa.PrivateKey:
+		if rsaAlg == AlgRSAV15SHA256 {
+			return NewRSA(id, k), nil
+		}
+		return NewRSAPSS(id, k), nil
+	default:
+		return nil, errs.ErrUnsupportedKey
+	}
+}
+
+//ParsePublicKeyPEM create validator from PEM encoded public key (PKIX, PKCS #1 or certificate),
+//RSA keys are used with rsa-pss-sha512
+func ParsePublicKeyPEM(id string, data []byte) (*AsymmetricSignature, error) {
+	key, err := parsePublicKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	return newVerifier(id, key, defaultRSAAlg)
+}
+
+func parsePublicKeyPEM(data []byte) (interface{}, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.WrapMessage(errs.ErrInvalidKey, "pem block not found")
+	}
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "pem type `%s`", block.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidKey)
+	}
+	return key, nil
+}
+
+func newVerifier(id string, key interface{}, rsaAlg string) (*AsymmetricSignature, error) {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return NewEd25519Verifier(id, k), nil
+	case *ecdsa.PublicKey:
+		if !supportedCurve(k.Curve) {
+			return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "curve `%s`", k.Curve.Params().Name)
+		}
+		return NewECDSAVerifier(id, k), nil
+	case *rsa.PublicKey:
+		if rsaAlg == AlgRSAV15SHA256 {
+			return NewRSAVerifier(id, k), nil
+		}
+		return NewRSAPSSVerifier(id, k), nil
+	default:
+		return nil, errs.ErrUnsupportedKey
+	}
+}
+
+func supportedCurve(c elliptic.Curve) bool {
+	return c == elliptic.P256() || c == elliptic.P384()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//JWK model of JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	D   string `json:"d,omitempty"`
+}
+
+//JWKSet model of JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+//ParseJWK create sign from JSON Web Key, private key is used if `d` is set (OKP and EC only),
+//RSA keys are used with rsa-v1_5-sha256 if `alg` is RS256, otherwise with rsa-pss-sha512
+func ParseJWK(data []byte) (*AsymmetricSignature, error) {
+	var v JWK
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidKey)
+	}
+	return v.Signature()
+}
+
+//ParseJWKSet create signs from JSON Web Key Set, keys with unsupported types are skipped
+func ParseJWKSet(data []byte) ([]*AsymmetricSignature, error) {
+	var v JWKSet
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidKey)
+	}
+	result := make([]*AsymmetricSignature, 0, len(v.Keys))
+	for _, k := range v.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		}
+		s, err := k.Signature()
+		if err != nil {
+			if errors.Is(err, errs.ErrUnsupportedKey) {
+				continue
+			}
+			return nil, errors.WrapMessage(err, "key `%s`", k.Kid)
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+//Signature create sign from JSON Web Key
+func (v JWK) Signature() (*AsymmetricSignature, error) {
+	switch v.Kty {
+	case "OKP":
+		if v.Crv != "Ed25519" {
+			return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "curve `%s`", v.Crv)
+		}
+		x, err := decodeJWKField(v.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `x`")
+		}
+		if len(v.D) > 0 {
+			d, err := decodeJWKField(v.D)
+			if err != nil || len(d) != ed25519.SeedSize {
+				return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `d`")
+			}
+			key := ed25519.NewKeyFromSeed(d)
+			if !bytes.Equal(key.Public().(ed25519.PublicKey), x) {
+				return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `d` does not match `x`")
+			}
+			return NewEd25519(v.Kid, key), nil
+		}
+		return NewEd25519Verifier(v.Kid, x), nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch v.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "curve `%s`", v.Crv)
+		}
+		x, err := decodeJWKInt(v.X)
+		if err != nil {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `x`")
+		}
+		y, err := decodeJWKInt(v.Y)
+		if err != nil {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `y`")
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "point is not on curve")
+		}
+		pub := ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if len(v.D) > 0 {
+			d, err := decodeJWKInt(v.D)
+			if err != nil {
+				return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `d`")
+			}
+			return NewECDSA(v.Kid, &ecdsa.PrivateKey{PublicKey: pub, D: d}), nil
+		}
+		return NewECDSAVerifier(v.Kid, &pub), nil
+
+	case "RSA":
+		n, err := decodeJWKInt(v.N)
+		if err != nil {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `n`")
+		}
+		e, err := decodeJWKInt(v.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.WrapMessage(errs.ErrInvalidKey, "field `e`")
+		}
+		alg := defaultRSAAlg
+		if v.Alg == "RS256" {
+			alg = AlgRSAV15SHA256
+		}
+		return newVerifier(v.Kid, &rsa.PublicKey{N: n, E: int(e.Int64())}, alg)
+	}
+	return nil, errors.WrapMessage(errs.ErrUnsupportedKey, "kty `%s`", v.Kty)
+}
+
+func decodeJWKField(v string) ([]byte, error) {
+	if len(v) == 0 {
+		return nil, errs.ErrInvalidKey
+	}
+	return base64.RawURLEncoding.DecodeString(v)
+}
+
+func decodeJWKInt(v string) (*big.Int, error) {
+	b, err := decodeJWKField(v)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,239 @@
+package signature
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-logger"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultReloadInterval = 10 * time.Second
+
+type (
+	//KeysConfig model of keys file
+	KeysConfig struct {
+		Keys []KeyConfig `yaml:"keys" json:"keys"`
+	}
+	//KeyConfig model of key version,
+	//HMAC algorithms use secret, asymmetric algorithms use PEM encoded private or public key
+	KeyConfig struct {
+		ID        string    `yaml:"id" json:"id"`
+		Version   string    `yaml:"version" json:"version"`
+		Algorithm string    `yaml:"algorithm" json:"algorithm"`
+		Secret    string    `yaml:"secret,omitempty" json:"secret,omitempty"`
+		PEM       string    `yaml:"pem,omitempty" json:"pem,omitempty"`
+		NotBefore time.Time `yaml:"not_before,omitempty" json:"not_before,omitempty"`
+		NotAfter  time.Time `yaml:"not_after,omitempty" json:"not_after,omitempty"`
+	}
+)
+
+//KeyVersion create key version from config
+func (v KeyConfig) KeyVersion() (KeyVersion, error) {
+	kv := KeyVersion{Version: v.Version, NotBefore: v.NotBefore, NotAfter: v.NotAfter}
+	if len(v.ID) == 0 {
+		return kv, errors.WrapMessage(errs.ErrInvalidKey, "empty key id")
+	}
+	switch v.Algorithm {
+	case "hmac-sha256":
+		kv.Signer = NewSHA256(v.ID, v.Secret)
+	case "hmac-sha512":
+		kv.Signer = NewSHA512(v.ID, v.Secret)
+	case "hmac-md5":
+		kv.Signer = NewMD5(v.ID, v.Secret)
+	case AlgEd25519, AlgECDSAP256SHA256, AlgECDSAP384SHA384, AlgRSAPSSSHA512, AlgRSAV15SHA256:
+		var (
+			s   *AsymmetricSignature
+			err error
+		)
+		if key, err0 := parsePrivateKeyPEM([]byte(v.PEM)); err0 == nil {
+			s, err = newSigner(v.ID, key, v.Algorithm)
+		} else if key, err1 := parsePublicKeyPEM([]byte(v.PEM)); err1 == nil {
+			s, err = newVerifier(v.ID, key, v.Algorithm)
+		} else {
+			err = err1
+		}
+		if err != nil {
+			return kv, errors.WrapMessage(err, "key `%s` version `%s`", v.ID, v.Version)
+		}
+		if s.Algorithm() != v.Algorithm {
+			return kv, errors.WrapMessage(errs.ErrInvalidKey, "key `%s` is not %s", v.ID, v.Algorithm)
+		}
+		kv.Signer = s
+	default:
+		return kv, errors.WrapMessage(errs.ErrUnsupportedKey, "algorithm `%s`", v.Algorithm)
+	}
+	if strings.HasPrefix(v.Algorithm, "hmac-") && len(v.Secret) == 0 {
+		return kv, errors.WrapMessage(errs.ErrInvalidKey, "key `%s` version `%s` has empty secret", v.ID, v.Version)
+	}
+	return kv, nil
+}
+
+//DecodeKeys decoding keys config, format is detected by file extension (json or yaml)
+func DecodeKeys(filename string, data []byte) ([]KeyVersion, error) {
+	var conf KeysConfig
+	var err error
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		err = json.Unmarshal(data, &conf)
+	} else {
+		err = yaml.Unmarshal(data, &conf)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make([]KeyVersion, 0, len(conf.Keys))
+	for _, k := range conf.Keys {
+		kv, err := k.KeyVersion()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, kv)
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//ticker runs function with interval until stopped
+type ticker struct {
+	status   int64
+	interval time.Duration
+	close    chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newTicker(interval time.Duration) *ticker {
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	return &ticker{interval: interval}
+}
+
+func (v *ticker) start(name string, init func() error, call func()) error {
+	if !atomic.CompareAndSwapInt64(&v.status, 0, 1) {
+		return errors.WrapMessage(errs.ErrServAlreadyRunning, "keys loader for %s", name)
+	}
+	if err := init(); err != nil {
+		atomic.StoreInt64(&v.status, 0)
+		return err
+	}
+	v.close = make(chan struct{})
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+
+		tick := time.NewTicker(v.interval)
+		defer tick.Stop()
+
+		for {
+			select {
+			case <-v.close:
+				return
+			case <-tick.C:
+				call()
+			}
+		}
+	}()
+	return nil
+}
+
+func (v *ticker) stop(name string) error {
+	if !atomic.CompareAndSwapInt64(&v.status, 1, 0) {
+		return errors.WrapMessage(errs.ErrServAlreadyStopped, "keys loader for %s", name)
+	}
+	close(v.close)
+	v.wg.Wait()
+	return nil
+}
+
+//FileLoader loading keys from file to storage with reloading on change,
+//only keys added by the loader are replaced on reload
+type FileLoader struct {
+	filename string
+	store    *Storage
+	log      logger.Logger
+	data     []byte
+	ids      map[string]struct{}
+	mod      time.Time
+	tick     *ticker
+	lock     sync.Mutex
+}
+
+//NewFileLoader init loader, file is checked for changes with interval
+func NewFileLoader(filename string, interval time.Duration, store *Storage, log logger.Logger) *FileLoader {
+	return &FileLoader{
+		filename: filename,
+		store:    store,
+		log:      log,
+		tick:     newTicker(interval),
+	}
+}
+
+//Load reading file and replacing keys loaded from file if file is changed
+func (v *FileLoader) Load() error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	fi, err := os.Stat(v.filename)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(v.filename)
+	if err != nil {
+		return err
+	}
+	if v.data != nil && bytes.Equal(v.data, data) {
+		v.mod = fi.ModTime()
+		return nil
+	}
+	list, err := DecodeKeys(v.filename, data)
+	if err != nil {
+		return err
+	}
+	v.ids = v.store.replaceKeys(v.ids, list)
+	v.data, v.mod = data, fi.ModTime()
+	return nil
+}
+
+func (v *FileLoader) changed() (bool, error) {
+	fi, err := os.Stat(v.filename)
+	if err != nil {
+		return false, err
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return !fi.ModTime().Equal(v.mod), nil
+}
+
+//Up loading keys and start watching of file
+func (v *FileLoader) Up() error {
+	return v.tick.start(v.filename, v.Load, v.reload)
+}
+
+//Down stop watching of file
+func (v *FileLoader) Down() error {
+	return v.tick.stop(v.filename)
+}
+
+func (v *FileLoader) reload() {
+	ok, err := v.changed()
+	if err == nil && ok {
+		if err = v.Load(); err == nil {
+			v.log.WithFields(logger.Fields{"file": v.filename}).Infof("keys reloaded")
+		}
+	}
+	if err != nil {
+		v.log.WithFields(logger.Fields{"err": err.Error(), "file": v.filename}).Errorf("keys reload")
+	}
+}
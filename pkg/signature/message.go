@@ -0,0 +1,508 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+)
+
+//HTTP Message Signatures (RFC 9421)
+
+const (
+	SignInputHeader     = `Signature-Input`
+	ContentDigestHeader = `Content-Digest`
+	defaultSignLabel    = `sig1`
+	signParamsComponent = `@signature-params`
+	digestComponent     = `content-digest`
+)
+
+//DefaultComponents covered by signature if not specified
+var DefaultComponents = []string{"@method", "@authority", "@path", "@query"}
+
+//MessageParams parameters of message signature
+type MessageParams struct {
+	Label      string
+	Components []string
+	Created    time.Time
+	Expires    time.Time
+	Nonce      string
+}
+
+//SignRequest signing request by RFC 9421, body is covered via Content-Digest header
+func SignRequest(r *http.Request, body []byte, s SignGetter, p MessageParams) error {
+	if len(p.Label) == 0 {
+		p.Label = defaultSignLabel
+	}
+	if len(p.Components) == 0 {
+		p.Components = append(make([]string, 0, len(DefaultComponents)+1), DefaultComponents...)
+		if len(body) > 0 {
+			p.Components = append(p.Components, digestComponent)
+		}
+	}
+	if p.Created.IsZero() {
+		p.Created = time.Now()
+	}
+	if len(p.Nonce) == 0 {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		p.Nonce = base64.RawURLEncoding.EncodeToString(b)
+	}
+	for _, c := range p.Components {
+		if strings.ToLower(c) == digestComponent {
+			r.Header.Set(ContentDigestHeader, "sha-256=:"+digestSHA256(body)+":")
+			break
+		}
+	}
+
+	params := encodeSignParams(p, s)
+	base, err := signatureBase(r, p.Components, params)
+	if err != nil {
+		return err
+	}
+	sign := s.Create(base)
+	if len(sign) == 0 {
+		return errors.WrapMessage(errs.ErrSignatureMismatch, "signer `%s` can not sign", s.ID())
+	}
+	r.Header.Set(SignInputHeader, p.Label+"="+params)
+	r.Header.Set(SignHeader, p.Label+"=:"+base64.StdEncoding.EncodeToString(sign)+":")
+	return nil
+}
+
+//VerifyOptions options of message signature verification
+type VerifyOptions struct {
+	//Label of signature, first signature is used if empty
+	Label string
+	//Required components which must be covered by signature
+	Required []string
+	//MaxAge of signature from created parameter, unlimited if zero
+	MaxAge time.Duration
+	//Skew allowed clock difference
+	Skew time.Duration
+	//Replay storage of used nonces, replay protection is disabled if nil,
+	//signature without expires and MaxAge is valid for 5 minutes from created
+	Replay ReplayChecker
+	//MaxBody max size of body for validation of content-digest, 10 MiB if zero
+	MaxBody int64
+}
+
+//VerifyRequest validation of request signature by RFC 9421, returns key ID
+func VerifyRequest(r *http.Request, store *Storage, opts VerifyOptions) (string, error) {
+	label, params, err := findSignParams(r.Header.Get(SignInputHeader), opts.Label)
+	if err != nil {
+		return "", err
+	}
+	sign, err := findSignValue(r.Header.Get(SignHeader), label)
+	if err != nil {
+		return "", err
+	}
+	sp, err := decodeSignParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	//with replay protection signature must expire, otherwise it can be replayed after nonce is forgotten
+	maxAge := opts.MaxAge
+	if opts.Replay != nil && maxAge <= 0 && sp.Expires.IsZero() {
+		maxAge = defaultReplayTTL
+	}
+
+	now := time.Now()
+	if !sp.Created.IsZero() {
+		if sp.Created.After(now.Add(opts.Skew)) {
+			return "", errors.WrapMessage(errs.ErrSignatureExpired, "created in future")
+		}
+		if maxAge > 0 && now.Sub(sp.Created) > maxAge+opts.Skew {
+			return "", errs.ErrSignatureExpired
+		}
+	} else if maxAge > 0 {
+		return "", errors.WrapMessage(errs.ErrInvalidSignature, "created parameter is required")
+	}
+	if !sp.Expires.IsZero() && now.After(sp.Expires.Add(opts.Skew)) {
+		return "", errs.ErrSignatureExpired
+	}
+
+	covered := make(map[string]struct{}, len(sp.Components))
+	for _, c := range sp.Components {
+		covered[c] = struct{}{}
+	}
+	for _, c := range opts.Required {
+		if _, ok := covered[strings.ToLower(c)]; !ok {
+			return "", errors.WrapMessage(errs.ErrInvalidSignature, "component `%s` is not covered", c)
+		}
+	}
+
+	base, err := signatureBase(r, sp.Components, params)
+	if err != nil {
+		return "", err
+	}
+	if _, err = store.Verify(sp.KeyID, sp.Alg, base, hex.EncodeToString(sign)); err != nil {
+		return "", err
+	}
+	//body is read only for request with valid signature
+	if _, ok := covered[digestComponent]; ok {
+		if err = verifyContentDigest(r, opts.MaxBody); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Replay != nil {
+		until := sp.Expires.Add(opts.Skew)
+		if maxAge > 0 {
+			until = sp.Created.Add(maxAge + opts.Skew)
+		}
+		nonce := sp.Nonce
+		if len(nonce) == 0 {
+			nonce = base64.StdEncoding.EncodeToString(sign)
+		}
+		if opts.Replay.Seen(sp.KeyID+":"+nonce, until) {
+			return "", errs.ErrSignatureReplayed
+		}
+	}
+	return sp.KeyID, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	defaultReplayTTL = 5 * time.Minute
+	defaultMaxBody   = 10 << 20
+)
+
+//ReplayChecker interface of nonce storage
+type ReplayChecker interface {
+	//Seen returns true if key is already used, otherwise stores key until time
+	Seen(key string, until time.Time) bool
+}
+
+var _ ReplayChecker = (*NonceCache)(nil)
+
+//NonceCache in-memory storage of used nonces
+type NonceCache struct {
+	list map[string]time.Time
+	next time.Time
+	lock sync.Mutex
+}
+
+//NewNonceCache init nonce storage
+func NewNonceCache() *NonceCache {
+	return &NonceCache{
+		list: make(map[string]time.Time),
+	}
+}
+
+//Seen check and store nonce
+func (v *NonceCache) Seen(key string, until time.Time) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	now := time.Now()
+	if now.After(v.next) {
+		for k, t := range v.list {
+			if now.After(t) {
+				delete(v.list, k)
+			}
+		}
+		v.next = now.Add(time.Minute)
+	}
+	if t, ok := v.list[key]; ok && !now.After(t) {
+		return true
+	}
+	v.list[key] = until
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type signParams struct {
+	Components []string
+	Created    time.Time
+	Expires    time.Time
+	Nonce      string
+	KeyID      string
+	Alg        string
+}
+
+func encodeSignParams(p MessageParams, s SignGetter) string {
+	var sb strings.Builder
+	sb.WriteString("(")
+	for i, c := range p.Components {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(strconv.Quote(strings.ToLower(c)))
+	}
+	sb.WriteString(");created=")
+	sb.WriteString(strconv.FormatInt(p.Created.Unix(), 10))
+	if !p.Expires.IsZero() {
+		sb.WriteString(";expires=")
+		sb.WriteString(strconv.FormatInt(p.Expires.Unix(), 10))
+	}
+	sb.WriteString(";nonce=")
+	sb.WriteString(strconv.Quote(p.Nonce))
+	sb.WriteString(";keyid=")
+	sb.WriteString(strconv.Quote(s.ID()))
+	sb.WriteString(";alg=")
+	sb.WriteString(strconv.Quote(s.Algorithm()))
+	return sb.String()
+}
+
+func decodeSignParams(v string) (p signParams, err error) {
+	end := strings.IndexByte(v, ')')
+	if !strings.HasPrefix(v, "(") || end < 0 {
+		err = errors.WrapMessage(errs.ErrInvalidSignature, "invalid inner list")
+		return
+	}
+	for _, c := range strings.Fields(v[1:end]) {
+		var uc string
+		if uc, err = strconv.Unquote(c); err != nil {
+			err = errors.WrapMessage(errs.ErrInvalidSignature, "invalid component %s", c)
+			return
+		}
+		p.Components = append(p.Components, uc)
+	}
+	for _, param := range strings.Split(v[end+1:], ";") {
+		if len(param) == 0 {
+			continue
+		}
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		val := kv[1]
+		if strings.HasPrefix(val, `"`) {
+			if val, err = strconv.Unquote(val); err != nil {
+				err = errors.WrapMessage(errs.ErrInvalidSignature, "invalid parameter %s", kv[0])
+				return
+			}
+		}
+		switch kv[0] {
+		case "created", "expires":
+			var ts int64
+			if ts, err = strconv.ParseInt(val, 10, 64); err != nil {
+				err = errors.WrapMessage(errs.ErrInvalidSignature, "invalid parameter %s", kv[0])
+				return
+			}
+			if kv[0] == "created" {
+				p.Created = time.Unix(ts, 0)
+			} else {
+				p.Expires = time.Unix(ts, 0)
+			}
+		case "nonce":
+			p.Nonce = val
+		case "keyid":
+			p.KeyID = val
+		case "alg":
+			p.Alg = val
+		}
+	}
+	if len(p.KeyID) == 0 {
+		err = errors.WrapMessage(errs.ErrInvalidSignature, "keyid parameter is required")
+	}
+	return
+}
+
+//findSignParams getting label and inner list from Signature-Input dictionary
+func findSignParams(h, label string) (string, string, error) {
+	for _, item := range splitDict(h) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if len(label) == 0 || kv[0] == label {
+			return kv[0], kv[1], nil
+		}
+	}
+	return "", "", errors.WrapMessage(errs.ErrInvalidSignature, "signature input not found")
+}
+
+//findSignValue getting signature by label from Signature dictionary
+func findSignValue(h, label string) ([]byte, error) {
+	for _, item := range splitDict(h) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] != label {
+			continue
+		}
+		return decodeByteSequence(kv[1])
+	}
+	return nil, errors.WrapMessage(errs.ErrInvalidSignature, "signature `%s` not found", label)
+}
+
+//splitDict split structured field dictionary by commas outside of strings and inner lists
+func splitDict(h string) []string {
+	result := make([]string, 0, 1)
+	quoted, depth, start := false, 0, 0
+	for i := 0; i < len(h); i++ {
+		switch h[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case '(':
+			if !quoted {
+				depth++
+			}
+		case ')':
+			if !quoted {
+				depth--
+			}
+		case ',':
+			if !quoted && depth == 0 {
+				result = append(result, strings.TrimSpace(h[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if v := strings.TrimSpace(h[start:]); len(v) > 0 {
+		result = append(result, v)
+	}
+	return result
+}
+
+func decodeByteSequence(v string) ([]byte, error) {
+	if len(v) < 2 || v[0] != ':' || v[len(v)-1] != ':' {
+		return nil, errors.WrapMessage(errs.ErrInvalidSignature, "invalid byte sequence")
+	}
+	b, err := base64.StdEncoding.DecodeString(v[1 : len(v)-1])
+	if err != nil {
+		return nil, errors.Wrap(err, errs.ErrInvalidSignature)
+	}
+	return b, nil
+}
+
+func signatureBase(r *http.Request, components []string, params string) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, c := range components {
+		c = strings.ToLower(c)
+		v, err := componentValue(r, c)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(strconv.Quote(c))
+		buf.WriteString(": ")
+		buf.WriteString(v)
+		buf.WriteString("\n")
+	}
+	buf.WriteString(strconv.Quote(signParamsComponent))
+	buf.WriteString(": ")
+	buf.WriteString(params)
+	return buf.Bytes(), nil
+}
+
+func componentValue(r *http.Request, c string) (string, error) {
+	switch c {
+	case "@method":
+		return r.Method, nil
+	case "@authority":
+		return strings.ToLower(requestHost(r)), nil
+	case "@scheme":
+		return requestScheme(r), nil
+	case "@path":
+		if p := r.URL.EscapedPath(); len(p) > 0 {
+			return p, nil
+		}
+		return "/", nil
+	case "@query":
+		return "?" + r.URL.RawQuery, nil
+	case "@request-target":
+		return r.URL.RequestURI(), nil
+	case "@target-uri":
+		return requestScheme(r) + "://" + strings.ToLower(requestHost(r)) + r.URL.RequestURI(), nil
+	}
+	if strings.HasPrefix(c, "@") {
+		return "", errors.WrapMessage(errs.ErrInvalidSignature, "unsupported component `%s`", c)
+	}
+	vals := r.Header.Values(c)
+	if len(vals) == 0 {
+		return "", errors.WrapMessage(errs.ErrInvalidSignature, "header `%s` is missing", c)
+	}
+	result := make([]string, 0, len(vals))
+	for _, v := range vals {
+		result = append(result, strings.TrimSpace(v))
+	}
+	return strings.Join(result, ", "), nil
+}
+
+func requestHost(r *http.Request) string {
+	if len(r.Host) > 0 {
+		return r.Host
+	}
+	return r.URL.Host
+}
+
+func requestScheme(r *http.Request) string {
+	if len(r.URL.Scheme) > 0 {
+		return strings.ToLower(r.URL.Scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func digestSHA256(b []byte) string {
+	h := sha256.Sum256(b)
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func digestSHA512(b []byte) string {
+	h := sha512.Sum512(b)
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+//verifyContentDigest validation of Content-Digest header (RFC 9530) with restoring of body,
+//body is read up to limit bytes
+func verifyContentDigest(r *http.Request, limit int64) error {
+	if limit <= 0 {
+		limit = defaultMaxBody
+	}
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(io.LimitReader(r.Body, limit+1)); err != nil {
+			return err
+		}
+		r.Body.Close() //nolint: errcheck
+		if int64(len(body)) > limit {
+			return errors.WrapMessage(errs.ErrBodyTooLarge, "limit %d bytes", limit)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	for _, item := range splitDict(r.Header.Get(ContentDigestHeader)) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || len(kv[1]) < 2 {
+			continue
+		}
+		var ex string
+		switch kv[0] {
+		case "sha-256":
+			ex = digestSHA256(body)
+		case "sha-512":
+			ex = digestSHA512(body)
+		default:
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(kv[1][1:len(kv[1])-1]), []byte(ex)) == 1 {
+			return nil
+		}
+		return errs.ErrInvalidContentDigest
+	}
+	return errs.ErrInvalidContentDigest
+}
@@ -0,0 +1,96 @@
+package signature_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_MessageSignature(t *testing.T) {
+	store := signature.NewStorage()
+	store.Add(signature.NewSHA256("1", "secret"))
+
+	body := []byte(`{"hello":"world"}`)
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/api/data?a=1", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		require.NoError(t, signature.SignRequest(req, body, signature.NewSHA256("1", "secret"), signature.MessageParams{
+			Components: []string{"@method", "@authority", "@path", "@query", "content-digest", "content-type"},
+			Expires:    time.Now().Add(time.Minute),
+		}))
+		return req
+	}
+
+	req := newReq()
+	require.Contains(t, req.Header.Get(signature.SignInputHeader),
+		`sig1=("@method" "@authority" "@path" "@query" "content-digest" "content-type");created=`)
+	require.Contains(t, req.Header.Get(signature.SignHeader), `sig1=:`)
+
+	opts := signature.VerifyOptions{
+		Required: []string{"@method", "@path", "content-digest"},
+		MaxAge:   time.Minute,
+		Skew:     time.Second,
+		Replay:   signature.NewNonceCache(),
+	}
+
+	id, err := signature.VerifyRequest(req, store, opts)
+	require.NoError(t, err)
+	require.Equal(t, "1", id)
+	b, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, body, b)
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	_, err = signature.VerifyRequest(req, store, opts)
+	require.True(t, errors.Is(err, errs.ErrSignatureReplayed))
+
+	req = newReq()
+	req.Method = http.MethodDelete
+	_, err = signature.VerifyRequest(req, store, opts)
+	require.True(t, errors.Is(err, errs.ErrSignatureMismatch))
+
+	req = newReq()
+	req.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{}`)))
+	_, err = signature.VerifyRequest(req, store, opts)
+	require.True(t, errors.Is(err, errs.ErrInvalidContentDigest))
+
+	req = newReq()
+	limited := opts
+	limited.MaxBody = int64(len(body)) - 1
+	_, err = signature.VerifyRequest(req, store, limited)
+	require.True(t, errors.Is(err, errs.ErrBodyTooLarge))
+
+	req = newReq()
+	req.Header.Set("Content-Type", "text/plain")
+	_, err = signature.VerifyRequest(req, store, opts)
+	require.True(t, errors.Is(err, errs.ErrSignatureMismatch))
+
+	req = newReq()
+	_, err = signature.VerifyRequest(req, signature.NewStorage(), opts)
+	require.True(t, errors.Is(err, errs.ErrSignatureKeyNotFound))
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	require.NoError(t, signature.SignRequest(req, nil, signature.NewSHA256("1", "secret"), signature.MessageParams{
+		Created: time.Now().Add(-time.Hour),
+	}))
+	_, err = signature.VerifyRequest(req, store, signature.VerifyOptions{MaxAge: time.Minute})
+	require.True(t, errors.Is(err, errs.ErrSignatureExpired))
+	_, err = signature.VerifyRequest(req, store, signature.VerifyOptions{Required: []string{"content-digest"}})
+	require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+	_, err = signature.VerifyRequest(req, store, signature.VerifyOptions{})
+	require.NoError(t, err)
+	_, err = signature.VerifyRequest(req, store, signature.VerifyOptions{Replay: signature.NewNonceCache()})
+	require.True(t, errors.Is(err, errs.ErrSignatureExpired))
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	_, err = signature.VerifyRequest(req, store, opts)
+	require.True(t, errors.Is(err, errs.ErrInvalidSignature))
+}
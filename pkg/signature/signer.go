@@ -7,18 +7,27 @@ import (
 	"crypto/sha512"
 	"encoding/hex"
 	"hash"
+	"io"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
 )
 
-var _ SignGetter = (*Signature)(nil)
+var (
+	_ SignGetter   = (*Signature)(nil)
+	_ StreamSigner = (*Signature)(nil)
+)
 
 type (
 	//Signature model
 	Signature struct {
-		id       string
-		hashFunc hash.Hash
-		alg      string
-		lock     sync.Mutex
+		id   string
+		alg  string
+		size int
+		pool sync.Pool
 	}
 	//SignGetter interface
 	SignGetter interface {
@@ -28,8 +37,19 @@ type (
 		CreateString(b []byte) string
 		Validate(b []byte, ex string) bool
 	}
+	//StreamSigner interface of signature without buffering of data
+	StreamSigner interface {
+		CreateReader(r io.Reader) ([]byte, error)
+		ValidateReader(r io.Reader, ex string) (bool, error)
+	}
 )
 
+type hmacItem struct {
+	mac hash.Hash
+	sum []byte
+	ex  []byte
+}
+
 //NewSHA256 create sign sha256
 func NewSHA256(id, secret string) *Signature {
 	return NewCustomSignature(id, secret, "hmac-sha256", sha256.New)
@@ -47,11 +67,30 @@ func NewSHA512(id, secret string) *Signature {
 
 //NewCustomSignature create sign with custom hash function
 func NewCustomSignature(id, secret, alg string, h func() hash.Hash) *Signature {
-	return &Signature{
-		id:       id,
-		alg:      alg,
-		hashFunc: hmac.New(h, []byte(secret)),
+	key := []byte(secret)
+	s := &Signature{
+		id:   id,
+		alg:  alg,
+		size: h().Size(),
+	}
+	s.pool.New = func() interface{} {
+		return &hmacItem{
+			mac: hmac.New(h, key),
+			sum: make([]byte, 0, s.size),
+			ex:  make([]byte, s.size),
+		}
 	}
+	return s
+}
+
+func (s *Signature) acquire() *hmacItem {
+	v := s.pool.Get().(*hmacItem)
+	v.mac.Reset()
+	return v
+}
+
+func (s *Signature) release(v *hmacItem) {
+	s.pool.Put(v)
 }
 
 //ID signature
@@ -66,13 +105,11 @@ func (s *Signature) Algorithm() string {
 
 //Create getting hash as bytes
 func (s *Signature) Create(b []byte) []byte {
-	s.lock.Lock()
-	defer func() {
-		s.hashFunc.Reset()
-		s.lock.Unlock()
-	}()
-	s.hashFunc.Write(b)
-	return s.hashFunc.Sum(nil)
+	v := s.acquire()
+	defer s.release(v)
+
+	v.mac.Write(b) //nolint: errcheck
+	return v.mac.Sum(make([]byte, 0, s.size))
 }
 
 //CreateString getting hash as string
@@ -82,47 +119,240 @@ func (s *Signature) CreateString(b []byte) string {
 
 //Validate signature
 func (s *Signature) Validate(b []byte, ex string) bool {
-	v, err := hex.DecodeString(ex)
-	if err != nil {
+	v := s.acquire()
+	defer s.release(v)
+
+	if !decodeHex(v.ex, ex) {
 		return false
 	}
-	return hmac.Equal(s.Create(b), v)
+	v.mac.Write(b) //nolint: errcheck
+	v.sum = v.mac.Sum(v.sum[:0])
+	return hmac.Equal(v.sum, v.ex)
+}
+
+//CreateReader getting hash of stream as bytes
+func (s *Signature) CreateReader(r io.Reader) ([]byte, error) {
+	v := s.acquire()
+	defer s.release(v)
+
+	if _, err := io.Copy(v.mac, r); err != nil {
+		return nil, err
+	}
+	return v.mac.Sum(make([]byte, 0, s.size)), nil
+}
+
+//ValidateReader signature of stream
+func (s *Signature) ValidateReader(r io.Reader, ex string) (bool, error) {
+	v := s.acquire()
+	defer s.release(v)
+
+	if !decodeHex(v.ex, ex) {
+		return false, nil
+	}
+	if _, err := io.Copy(v.mac, r); err != nil {
+		return false, err
+	}
+	v.sum = v.mac.Sum(v.sum[:0])
+	return hmac.Equal(v.sum, v.ex), nil
+}
+
+//decodeHex decoding of hex string to buffer of the exact size without allocations
+func decodeHex(dst []byte, src string) bool {
+	if len(src) != 2*len(dst) {
+		return false
+	}
+	for i := 0; i < len(dst); i++ {
+		a, ok1 := fromHexChar(src[2*i])
+		b, ok2 := fromHexChar(src[2*i+1])
+		if !ok1 || !ok2 {
+			return false
+		}
+		dst[i] = a<<4 | b
+	}
+	return true
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+type (
+	//KeyVersion model of versioned key
+	KeyVersion struct {
+		Version   string
+		Signer    SignGetter
+		NotBefore time.Time
+		NotAfter  time.Time
+	}
+	//VerifyEvent model of verification result for audit
+	VerifyEvent struct {
+		ID        string
+		Version   string
+		Algorithm string
+		Valid     bool
+	}
+)
+
+//Active key version at the time
+func (v KeyVersion) Active(t time.Time) bool {
+	return (v.NotBefore.IsZero() || !t.Before(v.NotBefore)) &&
+		(v.NotAfter.IsZero() || t.Before(v.NotAfter))
+}
+
 //Storage storage
 type Storage struct {
-	list map[string]SignGetter
-	lock sync.RWMutex
+	list     map[string][]KeyVersion
+	onVerify []func(VerifyEvent)
+	lock     sync.RWMutex
 }
 
 //NewStorage init storage
 func NewStorage() *Storage {
 	return &Storage{
-		list: make(map[string]SignGetter),
+		list:     make(map[string][]KeyVersion),
+		onVerify: make([]func(VerifyEvent), 0),
 	}
 }
 
-//Add adding to storage
+//Add adding to storage with replacing of all versions
 func (ss *Storage) Add(s SignGetter) {
 	ss.lock.Lock()
 	defer ss.lock.Unlock()
 
-	ss.list[s.ID()] = s
+	ss.list[s.ID()] = []KeyVersion{{Signer: s}}
 }
 
-//Get getting to storage
+//AddVersion adding version of key to storage, version with the same name is replaced
+func (ss *Storage) AddVersion(v KeyVersion) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	ss.list[v.Signer.ID()] = appendVersion(ss.list[v.Signer.ID()], v)
+}
+
+//Replace all keys in storage
+func (ss *Storage) Replace(list []KeyVersion) {
+	result := make(map[string][]KeyVersion, len(list))
+	for _, v := range list {
+		result[v.Signer.ID()] = appendVersion(result[v.Signer.ID()], v)
+	}
+
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	ss.list = result
+}
+
+//replaceKeys replacing versions of keys from list and deleting keys from prev which are not in list,
+//other keys are not changed, returns IDs of keys from list
+func (ss *Storage) replaceKeys(prev map[string]struct{}, list []KeyVersion) map[string]struct{} {
+	result := make(map[string][]KeyVersion, len(list))
+	for _, v := range list {
+		result[v.Signer.ID()] = appendVersion(result[v.Signer.ID()], v)
+	}
+	ids := make(map[string]struct{}, len(result))
+
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	for id := range prev {
+		delete(ss.list, id)
+	}
+	for id, versions := range result {
+		ss.list[id] = versions
+		ids[id] = struct{}{}
+	}
+	return ids
+}
+
+//appendVersion keeps versions sorted from newest to oldest
+func appendVersion(list []KeyVersion, v KeyVersion) []KeyVersion {
+	result := make([]KeyVersion, 0, len(list)+1)
+	result = append(result, v)
+	for _, item := range list {
+		if item.Version != v.Version {
+			result = append(result, item)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NotBefore.After(result[j].NotBefore)
+	})
+	return result
+}
+
+//Get getting newest active version from storage
 func (ss *Storage) Get(id string) SignGetter {
 	ss.lock.RLock()
 	defer ss.lock.RUnlock()
 
-	if s, ok := ss.list[id]; ok {
-		return s
+	now := time.Now()
+	for _, v := range ss.list[id] {
+		if v.Active(now) {
+			return v.Signer
+		}
 	}
 	return nil
 }
 
+//Versions getting all versions of key
+func (ss *Storage) Versions(id string) []KeyVersion {
+	ss.lock.RLock()
+	defer ss.lock.RUnlock()
+
+	return append(make([]KeyVersion, 0, len(ss.list[id])), ss.list[id]...)
+}
+
+//OnVerify event on verification of signature, example: audit of used key versions
+func (ss *Storage) OnVerify(cb func(VerifyEvent)) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	ss.onVerify = append(ss.onVerify, cb)
+}
+
+//Verify signature with any active version of key, alg is not checked if empty
+func (ss *Storage) Verify(id, alg string, b []byte, ex string) (string, error) {
+	ss.lock.RLock()
+	list, calls := ss.list[id], ss.onVerify
+	ss.lock.RUnlock()
+
+	event := VerifyEvent{ID: id, Algorithm: alg}
+	defer func() {
+		for _, fn := range calls {
+			fn(event)
+		}
+	}()
+
+	found, now := false, time.Now()
+	for _, v := range list {
+		if !v.Active(now) {
+			continue
+		}
+		found = true
+		if len(alg) > 0 && v.Signer.Algorithm() != alg {
+			continue
+		}
+		if v.Signer.Validate(b, ex) {
+			event.Version, event.Algorithm, event.Valid = v.Version, v.Signer.Algorithm(), true
+			return v.Version, nil
+		}
+	}
+	if !found {
+		return "", errors.WrapMessage(errs.ErrSignatureKeyNotFound, "key `%s`", id)
+	}
+	return "", errs.ErrSignatureMismatch
+}
+
 //Count count sign in storage
 func (ss *Storage) Count() int {
 	ss.lock.RLock()
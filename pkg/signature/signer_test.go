@@ -1,9 +1,19 @@
 package signature_test
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/deweppro/go-http/pkg/errs"
 	"github.com/deweppro/go-http/pkg/signature"
+	"github.com/deweppro/go-logger"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +26,46 @@ func TestUnit_Signature(t *testing.T) {
 	require.Equal(t, "123", sign.ID())
 	require.Equal(t, hash, sign.CreateString(body))
 	require.True(t, sign.Validate(body, hash))
+	require.True(t, sign.Validate(body, strings.ToUpper(hash)))
+	require.False(t, sign.Validate(body, hash[:10]))
+	require.False(t, sign.Validate(body, "zz"+hash[2:]))
+	require.False(t, sign.Validate([]byte("world"), hash))
+
+	b, err := sign.CreateReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Equal(t, sign.Create(body), b)
+	ok, err := sign.ValidateReader(bytes.NewReader(body), hash)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestUnit_SignatureConcurrency(t *testing.T) {
+	sign := signature.NewSHA256("123", "456")
+	hash := sign.CreateString([]byte("hello"))
+
+	var wg sync.WaitGroup
+	fails := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if got := sign.CreateString([]byte("hello")); got != hash {
+					fails <- "create: " + got
+					return
+				}
+				if !sign.Validate([]byte("hello"), hash) {
+					fails <- "validate"
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(fails)
+	for fail := range fails {
+		t.Error(fail)
+	}
 }
 
 func TestUnit_SignatureStorage(t *testing.T) {
@@ -36,3 +86,158 @@ func TestUnit_SignatureStorage(t *testing.T) {
 	require.Equal(t, "5", s.ID())
 	require.Equal(t, "hmac-md5", s.Algorithm())
 }
+
+func BenchmarkSignature_Create(b *testing.B) {
+	sign := signature.NewSHA256("123", "456")
+	body := bytes.Repeat([]byte("a"), 1024)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sign.Create(body)
+		}
+	})
+}
+
+func BenchmarkSignature_Validate(b *testing.B) {
+	sign := signature.NewSHA256("123", "456")
+	body := bytes.Repeat([]byte("a"), 1024)
+	hash := sign.CreateString(body)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if !sign.Validate(body, hash) {
+				b.Fatalf("invalid signature")
+			}
+		}
+	})
+}
+
+func BenchmarkSignature_ValidateReader(b *testing.B) {
+	sign := signature.NewSHA256("123", "456")
+	body := bytes.Repeat([]byte("a"), 1024*1024)
+	hash := sign.CreateString(body)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		r := bytes.NewReader(body)
+		for pb.Next() {
+			r.Reset(body)
+			if ok, err := sign.ValidateReader(r, hash); err != nil || !ok {
+				b.Fatalf("invalid signature")
+			}
+		}
+	})
+}
+
+func TestUnit_SignatureStorageVersions(t *testing.T) {
+	store := signature.NewStorage()
+	events := make([]signature.VerifyEvent, 0)
+	store.OnVerify(func(e signature.VerifyEvent) {
+		events = append(events, e)
+	})
+
+	now := time.Now()
+	store.AddVersion(signature.KeyVersion{Version: "1", Signer: signature.NewSHA256("a", "old")})
+	store.AddVersion(signature.KeyVersion{Version: "2", Signer: signature.NewSHA256("a", "new"), NotBefore: now.Add(-time.Minute)})
+	store.AddVersion(signature.KeyVersion{Version: "3", Signer: signature.NewSHA256("a", "future"), NotBefore: now.Add(time.Hour)})
+	store.AddVersion(signature.KeyVersion{Version: "4", Signer: signature.NewSHA256("a", "expired"), NotAfter: now.Add(-time.Minute)})
+	require.Equal(t, 1, store.Count())
+	require.Equal(t, 4, len(store.Versions("a")))
+
+	body := []byte("hello")
+	require.Equal(t, signature.NewSHA256("a", "new").CreateString(body), store.Get("a").CreateString(body))
+
+	ver, err := store.Verify("a", "hmac-sha256", body, signature.NewSHA256("a", "old").CreateString(body))
+	require.NoError(t, err)
+	require.Equal(t, "1", ver)
+
+	_, err = store.Verify("a", "", body, signature.NewSHA256("a", "future").CreateString(body))
+	require.True(t, errors.Is(err, errs.ErrSignatureMismatch))
+	_, err = store.Verify("a", "", body, signature.NewSHA256("a", "expired").CreateString(body))
+	require.True(t, errors.Is(err, errs.ErrSignatureMismatch))
+	_, err = store.Verify("a", "hmac-md5", body, signature.NewSHA256("a", "new").CreateString(body))
+	require.True(t, errors.Is(err, errs.ErrSignatureMismatch))
+	_, err = store.Verify("b", "", body, "")
+	require.True(t, errors.Is(err, errs.ErrSignatureKeyNotFound))
+
+	require.Equal(t, 5, len(events))
+	require.Equal(t, signature.VerifyEvent{ID: "a", Version: "1", Algorithm: "hmac-sha256", Valid: true}, events[0])
+	require.False(t, events[1].Valid)
+
+	store.Add(signature.NewMD5("a", "0"))
+	require.Equal(t, 1, len(store.Versions("a")))
+}
+
+func TestUnit_FileLoader(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`
+keys:
+  - id: partner
+    version: "1"
+    algorithm: hmac-sha256
+    secret: old
+    not_after: 2000-01-01T00:00:00Z
+  - id: partner
+    version: "2"
+    algorithm: hmac-sha256
+    secret: new
+    not_before: 2000-01-01T00:00:00Z
+`), 0600))
+
+	store := signature.NewStorage()
+	store.Add(signature.NewSHA256("manual", "secret"))
+	loader := signature.NewFileLoader(filename, 10*time.Millisecond, store, logger.Default())
+	require.NoError(t, loader.Up())
+	require.Error(t, loader.Up())
+
+	body := []byte("hello")
+	require.Equal(t, 2, len(store.Versions("partner")))
+	require.Equal(t, signature.NewSHA256("partner", "new").CreateString(body), store.Get("partner").CreateString(body))
+
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`
+keys:
+  - id: partner
+    version: "3"
+    algorithm: hmac-sha512
+    secret: newest
+`), 0600))
+	require.NoError(t, os.Chtimes(filename, time.Now(), time.Now().Add(time.Second)))
+
+	require.Eventually(t, func() bool {
+		s := store.Get("partner")
+		return s != nil && s.Algorithm() == "hmac-sha512"
+	}, time.Second, 10*time.Millisecond)
+	require.NotNil(t, store.Get("manual"))
+
+	//invalid file is retried until it is fixed even if modification time is not changed
+	mod := time.Now().Add(2 * time.Second)
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`keys: [{id: other, algorithm: unknown}]`), 0600))
+	require.NoError(t, os.Chtimes(filename, mod, mod))
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, ioutil.WriteFile(filename, []byte(`keys: [{id: other, algorithm: hmac-md5, secret: a}]`), 0600))
+	require.NoError(t, os.Chtimes(filename, mod, mod))
+
+	require.Eventually(t, func() bool {
+		return store.Get("other") != nil
+	}, time.Second, 10*time.Millisecond)
+	require.Nil(t, store.Get("partner"))
+	require.NotNil(t, store.Get("manual"))
+	require.NoError(t, loader.Down())
+	require.Error(t, loader.Down())
+
+	_, err := signature.DecodeKeys("keys.json", []byte(`{"keys":[{"id":"a","algorithm":"unknown"}]}`))
+	require.True(t, errors.Is(err, errs.ErrUnsupportedKey))
+	_, err = signature.DecodeKeys("keys.json", []byte(`{"keys":[{"id":"a","algorithm":"hmac-md5"}]}`))
+	require.True(t, errors.Is(err, errs.ErrInvalidKey))
+}
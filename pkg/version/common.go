@@ -3,31 +3,31 @@ package version
 import (
 	"fmt"
 	"net/http"
-	"regexp"
-	"strconv"
 )
 
 const (
-	VersionHeader      = `Accept`
-	versionValueRegexp = `application\/vnd.v(\d+)\+json`
-	versionValueTmpl   = `application/vnd.v%d+json`
+	VersionHeader    = `Accept`
+	versionValueTmpl = `application/vnd.v%d+json`
 )
 
-var rex = regexp.MustCompile(versionValueRegexp)
-
-//Decode getting version from header
+//Decode getting major version from header, the media range with the highest quality value is used
 func Decode(h http.Header) uint64 {
-	d := h.Get(VersionHeader)
-	r := rex.FindSubmatch([]byte(d))
-	if len(r) == 2 {
-		if v, err := strconv.ParseUint(string(r[1]), 10, 64); err == nil {
-			return v
-		}
+	if m, ok := DecodeMediaType(h); ok {
+		return m.Version.Major
 	}
 	return 0
 }
 
-//Encode setting version to header
+//DecodeMediaType getting versioned media type with the highest quality value from header
+func DecodeMediaType(h http.Header) (MediaType, bool) {
+	list := ParseAccept(h)
+	if len(list) == 0 {
+		return MediaType{}, false
+	}
+	return list[0], true
+}
+
+//Encode adding version to header
 func Encode(h http.Header, v uint64) {
-	h.Set(VersionHeader, fmt.Sprintf(versionValueTmpl, v))
+	h.Add(VersionHeader, fmt.Sprintf(versionValueTmpl, v))
 }
@@ -0,0 +1,64 @@
+package version_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/pkg/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnit_Decode(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   uint64
+		media  version.MediaType
+		ok     bool
+	}{
+		{name: "Case1", accept: "", want: 0},
+		{name: "Case2", accept: "application/vnd.v2+json", want: 2, ok: true,
+			media: version.MediaType{Version: version.Version{Major: 2}, Suffix: "json", Q: 1}},
+		{name: "Case3", accept: "application/vnd.acme.v2.1+xml", want: 2, ok: true,
+			media: version.MediaType{Vendor: "acme", Version: version.Version{Major: 2, Minor: 1}, Suffix: "xml", Q: 1}},
+		{name: "Case4", accept: "application/json; version=3", want: 3, ok: true,
+			media: version.MediaType{Version: version.Version{Major: 3}, Suffix: "json", Q: 1}},
+		{name: "Case5", accept: "text/html, application/vnd.v1+cbor;q=0.5, application/vnd.acme+json;version=4;q=0.9", want: 4, ok: true,
+			media: version.MediaType{Vendor: "acme", Version: version.Version{Major: 4}, Suffix: "json", Q: 0.9}},
+		{name: "Case6", accept: "application/vnd.v5+json;q=0, application/json", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("Accept", tt.accept)
+			require.Equal(t, tt.want, version.Decode(h))
+			media, ok := version.DecodeMediaType(h)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.media, media)
+		})
+	}
+}
+
+func TestUnit_Encode(t *testing.T) {
+	h := http.Header{}
+	h.Set("Accept", "text/html")
+	version.Encode(h, 2)
+	require.Equal(t, []string{"text/html", "application/vnd.v2+json"}, h.Values("Accept"))
+	require.Equal(t, uint64(2), version.Decode(h))
+
+	m := version.MediaType{Vendor: "acme", Version: version.Version{Major: 2, Minor: 1}, Suffix: "xml"}
+	require.Equal(t, "application/vnd.acme.v2.1+xml", m.String())
+}
+
+func TestUnit_Deprecation(t *testing.T) {
+	h := http.Header{}
+	version.Deprecation{
+		Date:   time.Unix(1688169599, 0),
+		Sunset: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Link:   "https://example.com/deprecation",
+	}.Encode(h)
+	require.Equal(t, "@1688169599", h.Get("Deprecation"))
+	require.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", h.Get("Sunset"))
+	require.Equal(t, `<https://example.com/deprecation>; rel="deprecation"; type="text/html"`, h.Get("Link"))
+}
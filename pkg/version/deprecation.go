@@ -0,0 +1,35 @@
+package version
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	DeprecationHeader = `Deprecation`
+	SunsetHeader      = `Sunset`
+)
+
+//Deprecation model of deprecation info for old API version
+type Deprecation struct {
+	//Date of deprecation, set `Deprecation: @<unix>` (RFC 9745)
+	Date time.Time `yaml:"date"`
+	//Sunset date of version removal, set `Sunset: <http-date>` (RFC 8594)
+	Sunset time.Time `yaml:"sunset"`
+	//Link to documentation about deprecation
+	Link string `yaml:"link"`
+}
+
+//Encode setting deprecation headers
+func (v Deprecation) Encode(h http.Header) {
+	if !v.Date.IsZero() {
+		h.Set(DeprecationHeader, fmt.Sprintf("@%d", v.Date.Unix()))
+	}
+	if !v.Sunset.IsZero() {
+		h.Set(SunsetHeader, v.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if len(v.Link) > 0 {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, v.Link))
+	}
+}
@@ -0,0 +1,144 @@
+package version
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var rexVendor = regexp.MustCompile(`^(?:(.+?)\.)?v(\d+)(?:\.(\d+))?$`)
+
+//Version model of semantic API version (major.minor)
+type Version struct {
+	Major uint64
+	Minor uint64
+}
+
+//ParseVersion parse version from string: 2, v2, v2.1
+func ParseVersion(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	major, minor := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		major, minor = s[:i], s[i+1:]
+	}
+	var (
+		v   Version
+		err error
+	)
+	if v.Major, err = strconv.ParseUint(major, 10, 64); err != nil {
+		return Version{}, false
+	}
+	if len(minor) > 0 {
+		if v.Minor, err = strconv.ParseUint(minor, 10, 64); err != nil {
+			return Version{}, false
+		}
+	}
+	return v, true
+}
+
+//String version as string: 2 or 2.1
+func (v Version) String() string {
+	if v.Minor == 0 {
+		return strconv.FormatUint(v.Major, 10)
+	}
+	return strconv.FormatUint(v.Major, 10) + "." + strconv.FormatUint(v.Minor, 10)
+}
+
+//Less version is lower than other
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	return v.Minor < o.Minor
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+//MediaType model of versioned media type
+type MediaType struct {
+	//Vendor name: acme for application/vnd.acme.v2+json
+	Vendor  string
+	Version Version
+	//Suffix format: json, xml, cbor
+	Suffix string
+	//Q quality value from Accept header
+	Q float64
+}
+
+//String media type as string: application/vnd.acme.v2.1+json
+func (v MediaType) String() string {
+	suffix := v.Suffix
+	if len(suffix) == 0 {
+		suffix = "json"
+	}
+	if len(v.Vendor) == 0 {
+		return fmt.Sprintf("application/vnd.v%s+%s", v.Version, suffix)
+	}
+	return fmt.Sprintf("application/vnd.%s.v%s+%s", v.Vendor, v.Version, suffix)
+}
+
+//ParseMediaType parse media range with version:
+//application/vnd.v2+json, application/vnd.acme.v2.1+xml, application/json; version=2
+func ParseMediaType(s string) (MediaType, bool) {
+	parts := strings.Split(s, ";")
+	m := MediaType{Q: 1}
+	typ := strings.ToLower(strings.TrimSpace(parts[0]))
+	i := strings.IndexByte(typ, '/')
+	if i < 0 {
+		return m, false
+	}
+	sub, found := typ[i+1:], false
+	if j := strings.LastIndexByte(sub, '+'); j >= 0 {
+		m.Suffix, sub = sub[j+1:], sub[:j]
+	} else if !strings.HasPrefix(sub, "vnd.") {
+		m.Suffix, sub = sub, ""
+	}
+	if strings.HasPrefix(sub, "vnd.") {
+		sub = sub[4:]
+		if r := rexVendor.FindStringSubmatch(sub); len(r) == 4 {
+			m.Vendor = r[1]
+			ver := r[2]
+			if len(r[3]) > 0 {
+				ver += "." + r[3]
+			}
+			m.Version, found = ParseVersion(ver)
+		} else {
+			m.Vendor = sub
+		}
+	}
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		val := strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case "version", "v":
+			if ver, ok := ParseVersion(val); ok {
+				m.Version, found = ver, true
+			}
+		case "q":
+			if q, err := strconv.ParseFloat(val, 64); err == nil && q >= 0 && q <= 1 {
+				m.Q = q
+			}
+		}
+	}
+	return m, found
+}
+
+//ParseAccept getting all versioned media types from header ordered by quality value
+func ParseAccept(h http.Header) []MediaType {
+	result := make([]MediaType, 0, 1)
+	for _, line := range h.Values(VersionHeader) {
+		for _, item := range strings.Split(line, ",") {
+			if m, ok := ParseMediaType(item); ok && m.Q > 0 {
+				result = append(result, m)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Q > result[j].Q })
+	return result
+}
@@ -14,4 +14,27 @@ type Config struct {
 	WriteTimeout    time.Duration `yaml:"write_timeout,omitempty"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout,omitempty"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout,omitempty"`
+	TLS             *TLSConfig    `yaml:"tls,omitempty"`
+}
+
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+//TLSConfig model
+type TLSConfig struct {
+	CertFile string `yaml:"cert"`
+	KeyFile  string `yaml:"key"`
+	//ClientCA file with CA certificates for verification of client certificates (mTLS)
+	ClientCA string `yaml:"client_ca,omitempty"`
+	//ClientAuth verification of client certificates: none (default), optional, require
+	ClientAuth string `yaml:"client_auth,omitempty"`
+	//MinVersion min TLS version: 1.0, 1.1, 1.2 (default), 1.3
+	MinVersion string `yaml:"min_version,omitempty"`
+	//CipherSuites names of cipher suites for TLS 1.2 and below (example: TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256)
+	CipherSuites []string `yaml:"cipher_suites,omitempty"`
+	//ReloadInterval interval of checking of files for changes, files are not reloaded if zero
+	ReloadInterval time.Duration `yaml:"reload_interval,omitempty"`
 }
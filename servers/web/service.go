@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"sync"
@@ -36,6 +37,7 @@ type Server struct {
 	serv    *http.Server
 	handler http.Handler
 	log     logger.Logger
+	tls     *tlsLoader
 	wg      sync.WaitGroup
 }
 
@@ -73,7 +75,7 @@ func (s *Server) validate() {
 	s.conf.Addr = internal.ValidateAddress(s.conf.Addr)
 }
 
-//Up start http server
+//Up start http server, https server is started if tls is configured
 func (s *Server) Up() error {
 	if !atomic.CompareAndSwapInt64(&s.status, servers.StatusOff, servers.StatusOn) {
 		return errors.WrapMessage(errs.ErrServAlreadyRunning, "starting server on %s", s.conf.Addr)
@@ -85,14 +87,30 @@ func (s *Server) Up() error {
 		Handler:      s.handler,
 	}
 
+	if s.conf.TLS != nil {
+		l, err := newTLSLoader(*s.conf.TLS, s.log)
+		if err != nil {
+			atomic.StoreInt64(&s.status, servers.StatusOff)
+			return err
+		}
+		s.tls = l
+		s.serv.TLSConfig = l.Config()
+		s.serv.Handler = identityHandler(s.handler)
+	}
+
 	nl, err := net.Listen(s.conf.Network, s.conf.Addr)
 	if err != nil {
+		atomic.StoreInt64(&s.status, servers.StatusOff)
 		return err
 	}
+	if s.tls != nil {
+		nl = tls.NewListener(nl, s.serv.TLSConfig)
+		s.tls.start()
+	}
 
 	s.wg.Add(1)
 	s.log.WithFields(logger.Fields{
-		"ip": s.conf.Addr,
+		"ip": s.conf.Addr, "tls": s.tls != nil,
 	}).Infof("http server started")
 
 	go func() {
@@ -119,5 +137,9 @@ func (s *Server) Down() error {
 	defer cncl()
 	err := s.serv.Shutdown(ctx)
 	s.wg.Wait()
+	if s.tls != nil {
+		s.tls.stop()
+		s.tls = nil
+	}
 	return err
 }
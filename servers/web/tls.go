@@ -0,0 +1,259 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deweppro/go-errors"
+	"github.com/deweppro/go-http/pkg/errs"
+	"github.com/deweppro/go-http/servers"
+	"github.com/deweppro/go-logger"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":                         tls.NoClientCert,
+	servers.ClientAuthNone:     tls.NoClientCert,
+	servers.ClientAuthOptional: tls.VerifyClientCertIfGiven,
+	servers.ClientAuthRequire:  tls.RequireAndVerifyClientCert,
+}
+
+//tlsLoader loading of certificate and client CA with reloading on change of files
+type tlsLoader struct {
+	conf  servers.TLSConfig
+	log   logger.Logger
+	base  *tls.Config
+	curr  *tls.Config
+	mods  []time.Time
+	close chan struct{}
+	wg    sync.WaitGroup
+	lock  sync.RWMutex
+}
+
+func newTLSLoader(conf servers.TLSConfig, log logger.Logger) (*tlsLoader, error) {
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(conf.MinVersion) > 0 {
+		v, ok := tlsVersions[conf.MinVersion]
+		if !ok {
+			return nil, errors.WrapMessage(errs.ErrInvalidTLSConfig, "unsupported min version `%s`", conf.MinVersion)
+		}
+		base.MinVersion = v
+	}
+	auth, ok := clientAuthTypes[strings.ToLower(conf.ClientAuth)]
+	if !ok {
+		return nil, errors.WrapMessage(errs.ErrInvalidTLSConfig, "unsupported client auth `%s`", conf.ClientAuth)
+	}
+	if auth != tls.NoClientCert && len(conf.ClientCA) == 0 {
+		return nil, errors.WrapMessage(errs.ErrInvalidTLSConfig, "client CA is required for client auth `%s`", conf.ClientAuth)
+	}
+	base.ClientAuth = auth
+	if len(conf.CipherSuites) > 0 {
+		suites, err := cipherSuites(conf.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		base.CipherSuites = suites
+	}
+	base.NextProtos = nextProtos(base)
+
+	l := &tlsLoader{conf: conf, log: log, base: base}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func cipherSuites(names []string) ([]uint16, error) {
+	all := make(map[string]uint16)
+	for _, cs := range tls.CipherSuites() {
+		all[cs.Name] = cs.ID
+	}
+	result := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := all[name]
+		if !ok {
+			return nil, errors.WrapMessage(errs.ErrInvalidTLSConfig, "unsupported cipher suite `%s`", name)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+//nextProtos protocols offered by ALPN, h2 is offered if cipher suites allow it
+//(server fails on start if h2 is offered without required cipher suite)
+func nextProtos(conf *tls.Config) []string {
+	if conf.CipherSuites == nil || conf.MinVersion >= tls.VersionTLS13 {
+		return []string{"h2", "http/1.1"}
+	}
+	for _, id := range conf.CipherSuites {
+		if id == tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 || id == tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+			return []string{"h2", "http/1.1"}
+		}
+	}
+	return []string{"http/1.1"}
+}
+
+//Config getting tls config for listener, certificates are taken from the latest loaded files
+func (v *tlsLoader) Config() *tls.Config {
+	conf := v.base.Clone()
+	conf.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		v.lock.RLock()
+		defer v.lock.RUnlock()
+		return v.curr, nil
+	}
+	return conf
+}
+
+func (v *tlsLoader) files() []string {
+	list := []string{v.conf.CertFile, v.conf.KeyFile}
+	if len(v.conf.ClientCA) > 0 {
+		list = append(list, v.conf.ClientCA)
+	}
+	return list
+}
+
+func (v *tlsLoader) changed() bool {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	for i, filename := range v.files() {
+		fi, err := os.Stat(filename)
+		if err != nil || !fi.ModTime().Equal(v.mods[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *tlsLoader) load() error {
+	files := v.files()
+	mods := make([]time.Time, 0, len(files))
+	for _, filename := range files {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return errors.Wrap(err, errs.ErrInvalidTLSConfig)
+		}
+		mods = append(mods, fi.ModTime())
+	}
+
+	cert, err := tls.LoadX509KeyPair(v.conf.CertFile, v.conf.KeyFile)
+	if err != nil {
+		return errors.Wrap(err, errs.ErrInvalidTLSConfig)
+	}
+	conf := v.base.Clone()
+	conf.Certificates = []tls.Certificate{cert}
+	if len(v.conf.ClientCA) > 0 {
+		b, err := os.ReadFile(v.conf.ClientCA)
+		if err != nil {
+			return errors.Wrap(err, errs.ErrInvalidTLSConfig)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return errors.WrapMessage(errs.ErrInvalidTLSConfig, "client CA has no certificates")
+		}
+		conf.ClientCAs = pool
+	}
+
+	v.lock.Lock()
+	v.curr, v.mods = conf, mods
+	v.lock.Unlock()
+	return nil
+}
+
+func (v *tlsLoader) start() {
+	if v.conf.ReloadInterval <= 0 {
+		return
+	}
+	v.close = make(chan struct{})
+	v.wg.Add(1)
+	go func() {
+		defer v.wg.Done()
+
+		tick := time.NewTicker(v.conf.ReloadInterval)
+		defer tick.Stop()
+
+		for {
+			select {
+			case <-v.close:
+				return
+			case <-tick.C:
+				if !v.changed() {
+					continue
+				}
+				if err := v.load(); err != nil {
+					v.log.WithFields(logger.Fields{
+						"err": err.Error(), "cert": v.conf.CertFile,
+					}).Errorf("reload tls certificates")
+					continue
+				}
+				v.log.WithFields(logger.Fields{
+					"cert": v.conf.CertFile,
+				}).Infof("tls certificates reloaded")
+			}
+		}
+	}()
+}
+
+func (v *tlsLoader) stop() {
+	if v.close == nil {
+		return
+	}
+	close(v.close)
+	v.wg.Wait()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type clientIdentityKey struct{}
+
+//Identity model of verified client certificate
+type Identity struct {
+	CommonName   string
+	Organization []string
+	DNSNames     []string
+	Emails       []string
+	URIs         []string
+	SerialNumber string
+	Certificate  *x509.Certificate
+}
+
+//ClientIdentity getting identity of verified client certificate for current request
+func ClientIdentity(r *http.Request) (*Identity, bool) {
+	v, ok := r.Context().Value(clientIdentityKey{}).(*Identity)
+	return v, ok
+}
+
+//identityHandler setting identity of verified client certificate to context
+func identityHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cert := r.TLS.VerifiedChains[0][0]
+		id := &Identity{
+			CommonName:   cert.Subject.CommonName,
+			Organization: cert.Subject.Organization,
+			DNSNames:     cert.DNSNames,
+			Emails:       cert.EmailAddresses,
+			URIs:         make([]string, 0, len(cert.URIs)),
+			SerialNumber: cert.SerialNumber.String(),
+			Certificate:  cert,
+		}
+		for _, u := range cert.URIs {
+			id.URIs = append(id.URIs, u.String())
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIdentityKey{}, id)))
+	})
+}
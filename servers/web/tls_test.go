@@ -0,0 +1,176 @@
+package web_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deweppro/go-http/internal"
+	"github.com/deweppro/go-http/servers"
+	"github.com/deweppro/go-http/servers/web"
+	"github.com/deweppro/go-logger"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+	kpem []byte
+}
+
+func newTestCert(t *testing.T, cn string, serial int64, parent *testCert, ca bool) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"test"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		IsCA:         ca,
+
+		BasicConstraintsValid: true,
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	kb, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		kpem: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}),
+	}
+}
+
+func TestUnit_ServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", 1, nil, true)
+	srvCert := newTestCert(t, "server-1", 2, ca, false)
+	cliCert := newTestCert(t, "client", 3, ca, false)
+
+	write := func(name string, b []byte) string {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, b, 0600))
+		return filename
+	}
+	conf := &servers.TLSConfig{
+		CertFile:       write("server.crt", srvCert.pem),
+		KeyFile:        write("server.key", srvCert.kpem),
+		ClientCA:       write("ca.crt", ca.pem),
+		ClientAuth:     servers.ClientAuthOptional,
+		MinVersion:     "1.2",
+		ReloadInterval: 20 * time.Millisecond,
+	}
+
+	addr, err := internal.RandomPort("127.0.0.1")
+	require.NoError(t, err)
+	srv := web.New(servers.Config{Addr: addr, TLS: conf}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := web.ClientIdentity(r); ok {
+			w.Write([]byte(id.CommonName)) //nolint: errcheck
+			return
+		}
+		w.Write([]byte("anonymous")) //nolint: errcheck
+	}), logger.Default())
+	require.NoError(t, srv.Up())
+	defer srv.Down() //nolint: errcheck
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	call := func(certs ...tls.Certificate) (string, string, error) {
+		cli := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs: roots, Certificates: certs,
+		}}}
+		resp, err := cli.Get("https://" + addr + "/")
+		if err != nil {
+			return "", "", err
+		}
+		b, err := internal.ReadAll(resp.Body)
+		return string(b), resp.TLS.PeerCertificates[0].Subject.CommonName, err
+	}
+
+	body, server, err := call()
+	require.NoError(t, err)
+	require.Equal(t, "anonymous", body)
+	require.Equal(t, "server-1", server)
+
+	h2 := &http.Client{Transport: &http.Transport{ForceAttemptHTTP2: true, TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	resp, err := h2.Get("https://" + addr + "/")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, 2, resp.ProtoMajor)
+
+	//h2 is not offered without required cipher suite
+	addr2, err := internal.RandomPort("127.0.0.1")
+	require.NoError(t, err)
+	conf2 := *conf
+	conf2.CipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"}
+	conf2.MinVersion, conf2.ReloadInterval = "1.2", 0
+	srv2 := web.New(servers.Config{Addr: addr2, TLS: &conf2}, http.NotFoundHandler(), logger.Default())
+	require.NoError(t, srv2.Up())
+	defer srv2.Down() //nolint: errcheck
+	require.Eventually(t, func() bool {
+		resp, err = h2.Get("https://" + addr2 + "/")
+		return err == nil
+	}, time.Second, 20*time.Millisecond)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, 1, resp.ProtoMajor)
+
+	pair, err := tls.X509KeyPair(cliCert.pem, cliCert.kpem)
+	require.NoError(t, err)
+	body, _, err = call(pair)
+	require.NoError(t, err)
+	require.Equal(t, "client", body)
+
+	srvCert2 := newTestCert(t, "server-2", 4, ca, false)
+	write("server.crt", srvCert2.pem)
+	write("server.key", srvCert2.kpem)
+	future := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(conf.CertFile, future, future))
+	require.NoError(t, os.Chtimes(conf.KeyFile, future, future))
+
+	require.Eventually(t, func() bool {
+		_, server, err = call()
+		return err == nil && server == "server-2"
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestUnit_ServerTLSConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		conf servers.TLSConfig
+	}{
+		{name: "Case1", conf: servers.TLSConfig{CertFile: "none.crt", KeyFile: "none.key"}},
+		{name: "Case2", conf: servers.TLSConfig{ClientAuth: servers.ClientAuthRequire}},
+		{name: "Case3", conf: servers.TLSConfig{MinVersion: "2.0"}},
+		{name: "Case4", conf: servers.TLSConfig{CipherSuites: []string{"UNKNOWN"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := tt.conf
+			srv := web.New(servers.Config{Addr: "127.0.0.1", TLS: &conf}, http.NotFoundHandler(), logger.Default())
+			require.Error(t, srv.Up())
+			require.Error(t, srv.Down())
+		})
+	}
+}